@@ -1,12 +1,18 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"log"
+	"os"
 	"path/filepath"
+	"smart-shift-scheduler/internal/domain"
 	"smart-shift-scheduler/internal/handler"
 	"smart-shift-scheduler/internal/infrastructure/database"
 	"smart-shift-scheduler/internal/infrastructure/engine"
+	"smart-shift-scheduler/internal/infrastructure/notifier"
 	"smart-shift-scheduler/internal/usecase"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
@@ -16,46 +22,218 @@ func main() {
 	scriptPath := filepath.Join("..", "engine", "main.py")
 	shiftEngine := engine.NewShiftEngine(scriptPath)
 
+	// 監査ログ
+	auditRepo := database.NewAuditRepository(db)
+	auditUsecase := usecase.NewAuditUsecase(auditRepo)
+	auditHandler := handler.NewAuditHandler(auditUsecase)
+
 	// Staff
 	staffRepo := database.NewStaffRepository(db)
 	staffUsecase := usecase.NewStaffUsecase(staffRepo)
-	staffHandler := handler.NewStaffHandler(staffUsecase)
+	staffHandler := handler.NewStaffHandler(staffUsecase, auditUsecase)
 
 	// Shift & Request & Requirement (★ここを拡張)
 	shiftRepo := database.NewShiftRepository(db)
 	requestRepo := database.NewRequestRepository(db)
 	requireRepo := database.NewRequirementRepository(db) // ★追加1: 必要人数の保存場所
+	pairRepo := database.NewPairRuleRepository(db)
+	dayOffRepo := database.NewDayOffRuleRepository(db)
+	periodRepo := database.NewPeriodRepository(db)
+	versionRepo := database.NewVersionRepository(db)
+	roleRepo := database.NewRoleConstraintRepository(db)
+	swapRepo := database.NewSwapRepository(db)
+	openRepo := database.NewOpenShiftRepository(db)
 	
 	// ★追加2: 引数が5つになりました (engine, staffRepo, shiftRepo, requestRepo, requireRepo)
-	shiftUsecase := usecase.NewShiftUsecase(shiftEngine, staffRepo, shiftRepo, requestRepo, requireRepo)
+	shiftUsecase := usecase.NewShiftUsecase(shiftEngine, staffRepo, shiftRepo, requestRepo, requireRepo, pairRepo, dayOffRepo, periodRepo, versionRepo, roleRepo, openRepo)
 	
-	shiftHandler := handler.NewShiftHandler(shiftUsecase)
-	requestHandler := handler.NewRequestHandler(shiftUsecase)
+	// 通知（アウトボックスに積んで、定期的に送る）
+	senders := map[string]usecase.NotificationSender{
+		domain.ChannelWebhook: notifier.NewWebhookSender(os.Getenv("WEBHOOK_SECRET")),
+		domain.ChannelFake:    notifier.NewFakeSender(),
+	}
+	if host := os.Getenv("SMTP_HOST"); host != "" {
+		port := os.Getenv("SMTP_PORT")
+		if port == "" {
+			port = "587"
+		}
+		senders[domain.ChannelEmail] = notifier.NewSMTPSender(host, port, os.Getenv("SMTP_USER"), os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_FROM"))
+	}
+	notificationRepo := database.NewNotificationRepository(db)
+	notificationUsecase := usecase.NewNotificationUsecase(notificationRepo, staffRepo, periodRepo, senders)
+	notificationHandler := handler.NewNotificationHandler(notificationUsecase)
+	go notificationUsecase.Run(context.Background(), 30*time.Second)
+
+	shiftHandler := handler.NewShiftHandler(shiftUsecase, auditUsecase, notificationUsecase)
+	requestHandler := handler.NewRequestHandler(shiftUsecase, auditUsecase, notificationUsecase)
+	pairRuleHandler := handler.NewPairRuleHandler(shiftUsecase)
+	dayOffRuleHandler := handler.NewDayOffRuleHandler(shiftUsecase)
+	roleConstraintHandler := handler.NewRoleConstraintHandler(shiftUsecase)
+
+	// Swap (申請 → 相手の承諾 → 店長の承認)
+	swapUsecase := usecase.NewSwapUsecase(swapRepo, shiftUsecase)
+	swapHandler := handler.NewSwapHandler(swapUsecase, auditUsecase, notificationUsecase)
+
+	// Open shift (埋まらなかった枠・手放されたシフトの募集)
+	openShiftUsecase := usecase.NewOpenShiftUsecase(openRepo, shiftUsecase)
+	openShiftHandler := handler.NewOpenShiftHandler(openShiftUsecase, auditUsecase, notificationUsecase)
+
+	// Call-out (当日欠勤の代わりの人探し)
+	callOutRepo := database.NewCallOutRepository(db)
+	replacementUsecase := usecase.NewReplacementUsecase(callOutRepo, shiftUsecase)
+	replacementHandler := handler.NewReplacementHandler(replacementUsecase, auditUsecase, notificationUsecase)
+
+	// Period (下書き → 公開 → 確定)
+	periodUsecase := usecase.NewPeriodUsecase(periodRepo, shiftRepo, versionRepo, staffRepo)
+	periodHandler := handler.NewPeriodHandler(periodUsecase, auditUsecase, notificationUsecase)
+
+	// カレンダー購読（公開済みシフトを iCalendar で配信）
+	calendarRepo := database.NewCalendarRepository(db)
+	calendarUsecase := usecase.NewCalendarUsecase(calendarRepo, staffRepo, periodRepo)
+	calendarHandler := handler.NewCalendarHandler(calendarUsecase)
+
+	// バックアップ・復元（データ全体を1つのJSONファイルで）
+	backupUsecase := usecase.NewBackupUsecase(database.NewBackupRepository(db))
+	backupHandler := handler.NewBackupHandler(backupUsecase, auditUsecase)
+
+	// ログイン（アカウントが1件も無ければ OWNER_USERNAME / OWNER_PASSWORD で最初のオーナーを作る）
+	authUsecase := usecase.NewAuthUsecase(database.NewUserRepository(db), staffRepo)
+	authHandler := handler.NewAuthHandler(authUsecase, auditUsecase)
+	if created, err := authUsecase.Bootstrap(os.Getenv("OWNER_USERNAME"), os.Getenv("OWNER_PASSWORD")); err != nil {
+		log.Fatal("オーナーアカウントの作成に失敗しました:", err)
+	} else if created {
+		fmt.Println("オーナーアカウントを作成しました:", os.Getenv("OWNER_USERNAME"))
+	}
 
 	r := gin.Default()
 	r.Static("/web", "../frontend")
+	r.GET("/ical/:file", calendarHandler.Feed) // /ical/<token>.ics
 
-	api := r.Group("/api")
+	// ログインしていなくても使えるのはログインだけ
+	r.POST("/api/auth/login", authHandler.Login)
+
+	// api: ログイン中なら誰でも（スタッフは閲覧と自分の申請だけ。本人かどうかは各Handlerでも確かめる）
+	// manager: 店長以上（生成・編集・承認・設定）、owner: オーナーだけ（バックアップと復元）
+	api := r.Group("/api", authHandler.Authenticate)
+	manager := api.Group("", handler.RequireRole(domain.RoleManager))
+	owner := api.Group("", handler.RequireRole(domain.RoleOwner))
+	self := handler.RequireSelf("id") // /staff/:id/... は本人か店長以上
 	{
-		api.POST("/staff", staffHandler.Create)
+		api.GET("/auth/me", authHandler.Me)
+		api.POST("/auth/logout", authHandler.Logout)
+		api.POST("/auth/password", authHandler.ChangePassword)
+
+		// アカウント（店長はスタッフのアカウントだけ作成・変更できる）
+		manager.GET("/users", authHandler.ListUsers)
+		manager.POST("/users", authHandler.CreateUser)
+		manager.PUT("/users/:id", authHandler.UpdateUser)
+		manager.DELETE("/users/:id", authHandler.DeleteUser)
+
+		manager.POST("/staff", staffHandler.Create)
+		manager.POST("/staff/import", staffHandler.Import)
 		api.GET("/staff", staffHandler.List)
-		api.DELETE("/staff/:id", staffHandler.Delete)
+		manager.DELETE("/staff/:id", staffHandler.Delete)
 		
-		api.POST("/shift", shiftHandler.Generate)
-		api.GET("/shift", shiftHandler.List)
-		api.PUT("/shift/:id", shiftHandler.Update)
-		api.DELETE("/shift/:id", shiftHandler.Delete)
+		manager.POST("/shift", shiftHandler.Generate)
+		manager.GET("/shift", shiftHandler.List) // 下書きも含む。スタッフは公開済みのシフト（/period/:id/shifts）を見る
+		manager.PUT("/shift/:id", shiftHandler.Update)
+		manager.PUT("/shift/:id/lock", shiftHandler.Lock)
+		manager.DELETE("/shift/:id", shiftHandler.Delete)
+
+		// 保存済みシフトの評価（ルール違反・KPI）
+		manager.GET("/schedule/evaluate", shiftHandler.Evaluate)
+		manager.GET("/schedule/coverage", shiftHandler.Coverage)
 
 		api.POST("/request", requestHandler.Create)
+		manager.POST("/request/import", requestHandler.Import)
 		api.GET("/request", requestHandler.List)
 		api.DELETE("/request/:id", requestHandler.Delete)
+		manager.POST("/request/:id/approve", requestHandler.Approve)
+		manager.POST("/request/:id/reject", requestHandler.Reject)
+
+		// 有給休暇（付与・残日数・年5日の取得義務）
+		manager.GET("/paid-leave", requestHandler.PaidLeave)
+		api.GET("/staff/:id/paid-leave", self, requestHandler.StaffPaidLeave)
+
+		// カレンダー購読URL
+		api.GET("/staff/:id/calendar", self, calendarHandler.Token)
+		api.POST("/staff/:id/calendar/rotate", self, calendarHandler.Rotate)
 
 		// ★追加3: 必要人数設定のAPI
-		api.POST("/requirement", shiftHandler.SaveRequirement)
+		manager.POST("/requirement", shiftHandler.SaveRequirement)
+		manager.POST("/requirement/import", shiftHandler.ImportRequirements)
 		api.GET("/requirement", shiftHandler.ListRequirements)
-		api.DELETE("/requirement/:id", shiftHandler.DeleteRequirement) // 追加
+		manager.DELETE("/requirement/:id", shiftHandler.DeleteRequirement) // 追加
+
+		// ペアルール（一緒に入る／一緒に入らない）
+		manager.POST("/pair-rule", pairRuleHandler.Create)
+		manager.GET("/pair-rule", pairRuleHandler.List)
+		manager.DELETE("/pair-rule/:id", pairRuleHandler.Delete)
+
+		// 募集シフト
+		api.POST("/shift/:id/drop", openShiftHandler.Drop)
+		manager.POST("/open-shift", openShiftHandler.Create)
+		api.GET("/open-shift", openShiftHandler.List)
+		api.GET("/open-shift/:id", openShiftHandler.Get)
+		api.POST("/open-shift/:id/claim", openShiftHandler.Claim)
+		manager.POST("/open-shift/:id/award", openShiftHandler.Award)
+		manager.POST("/open-shift/:id/close", openShiftHandler.Close)
+
+		// 当日欠勤（代わりの人の候補と付け替え）
+		manager.GET("/shift/:id/replacements", replacementHandler.List)
+		manager.POST("/shift/:id/reassign", replacementHandler.Reassign)
+		manager.GET("/call-out", replacementHandler.ListCallOuts)
+
+		// シフト交換（申請 → 相手の承諾 → 店長の承認）
+		api.POST("/swap", swapHandler.Create)
+		api.GET("/swap", swapHandler.List)
+		api.GET("/swap/:id", swapHandler.Get)
+		api.POST("/swap/:id/accept", swapHandler.Accept)
+		api.POST("/swap/:id/decline", swapHandler.Decline)
+		api.POST("/swap/:id/cancel", swapHandler.Cancel)
+		manager.POST("/swap/:id/approve", swapHandler.Approve)
+		manager.POST("/swap/:id/reject", swapHandler.Reject)
+
+		// 役割ごとの人数ルール
+		manager.POST("/role-constraint", roleConstraintHandler.Create)
+		manager.GET("/role-constraint", roleConstraintHandler.List)
+		manager.DELETE("/role-constraint/:id", roleConstraintHandler.Delete)
+
+		// 休日ルール（週休N日・連休）
+		manager.POST("/day-off-rule", dayOffRuleHandler.Create)
+		manager.GET("/day-off-rule", dayOffRuleHandler.List)
+		manager.DELETE("/day-off-rule/:id", dayOffRuleHandler.Delete)
+
+		// シフト期間
+		manager.POST("/period", periodHandler.Create)
+		api.GET("/period", periodHandler.List)
+		api.GET("/period/:id", periodHandler.Get)
+		manager.DELETE("/period/:id", periodHandler.Delete)
+		manager.POST("/period/:id/publish", periodHandler.Publish)
+		manager.POST("/period/:id/lock", periodHandler.Lock)
+		manager.POST("/period/:id/reopen", periodHandler.Reopen)
+		api.GET("/period/:id/shifts", periodHandler.PublishedShifts)
+
+		// バージョン（スナップショット・差分・巻き戻し）
+		manager.POST("/period/:id/versions", periodHandler.SaveVersion)
+		manager.GET("/period/:id/versions", periodHandler.ListVersions)
+		manager.GET("/period/:id/versions/diff", periodHandler.DiffVersions)
+		manager.GET("/period/:id/versions/:version", periodHandler.GetVersion)
+		manager.POST("/period/:id/versions/:version/rollback", periodHandler.Rollback)
 	
-		api.GET("/export", shiftHandler.Export)
+		manager.GET("/export", shiftHandler.Export)
+
+		manager.GET("/audit", auditHandler.List)
+
+		owner.GET("/backup", backupHandler.Export)
+		owner.POST("/restore", backupHandler.Restore)
+
+		// 通知（受け取り方の設定・送信状況・再送）
+		api.GET("/staff/:id/notification-preference", self, notificationHandler.GetPreference)
+		api.PUT("/staff/:id/notification-preference", self, notificationHandler.SavePreference)
+		manager.GET("/notification", notificationHandler.List)
+		manager.POST("/notification/dispatch", notificationHandler.Dispatch)
+		manager.POST("/notification/:id/retry", notificationHandler.Retry)
 	}
 
 	fmt.Println("サーバーを起動します... http://localhost:8080/web/index.html")
@@ -0,0 +1,69 @@
+package domain
+
+import "time"
+
+// ログインユーザーの権限
+const (
+	RoleOwner   = "owner"   // オーナー（全操作・アカウント管理・バックアップと復元）
+	RoleManager = "manager" // 店長（シフトの生成・編集・承認）
+	RoleStaff   = "staff"   // スタッフ（シフトの閲覧と自分の申請だけ）
+)
+
+// roleRanks: 権限の強さ（大きいほど強い）
+var roleRanks = map[string]int{RoleStaff: 1, RoleManager: 2, RoleOwner: 3}
+
+// ValidRole: 権限の名前として正しいか
+func ValidRole(role string) bool {
+	return roleRanks[role] > 0
+}
+
+// User: ログインアカウント（スタッフと紐づける。オーナーは紐づけなくてもよい）
+type User struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	Username     string    `gorm:"uniqueIndex" json:"username"`
+	PasswordHash string    `json:"-"` // bcrypt
+	Role         string    `json:"role"`
+	StaffID      int       `gorm:"index" json:"staff_id"` // 0ならスタッフと紐づけない
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// HasRole: role 以上の権限を持っているか
+func (u *User) HasRole(role string) bool {
+	return roleRanks[u.Role] >= roleRanks[role]
+}
+
+// IsManager: 店長以上（他のスタッフの分も操作できる）
+func (u *User) IsManager() bool {
+	return u.HasRole(RoleManager)
+}
+
+// CanActAs: スタッフ本人として操作できるか（店長以上は誰の分でも）
+func (u *User) CanActAs(staffID int) bool {
+	return u.IsManager() || (u.StaffID != 0 && u.StaffID == staffID)
+}
+
+// CanManage: role のアカウントを作成・変更・削除できるか
+// オーナーはすべて、店長はスタッフのアカウントだけ
+func (u *User) CanManage(role string) bool {
+	if u.Role == RoleOwner {
+		return true
+	}
+	return u.IsManager() && role == RoleStaff
+}
+
+// Session: ログイン中のセッション（合言葉そのものは保存せず、SHA-256 のハッシュだけを持つ）
+type Session struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	TokenHash string    `gorm:"uniqueIndex" json:"-"`
+	UserID    uint      `gorm:"index" json:"-"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// LoginResult: ログインの結果（Token はこのときだけ返す）
+type LoginResult struct {
+	Token     string    `json:"token"`
+	ExpiresAt time.Time `json:"expires_at"`
+	User      User      `json:"user"`
+}
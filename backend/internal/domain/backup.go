@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// バックアップファイルの形式
+const (
+	BackupFormat  = "smart-shift-scheduler-backup"
+	BackupVersion = 1 // 中身の形が変わったら上げ、古い版は復元時に読み替える
+)
+
+// Backup: データ全体のバックアップ（環境の移行・自動生成の失敗からの復旧用）
+// 監査ログ・通知の送信待ち・カレンダーの合言葉・ログインアカウントは含めない（履歴と、環境ごとに作り直すもの）
+type Backup struct {
+	Format    string     `json:"format"`
+	Version   int        `json:"version"`
+	CreatedAt time.Time  `json:"created_at"`
+	Data      BackupData `json:"data"`
+}
+
+// BackupData: テーブルごとの全件。ID は復元先で振り直し、参照先の ID も読み替える
+type BackupData struct {
+	Staff                   []Staff                  `json:"staff"`
+	Periods                 []SchedulePeriod         `json:"periods"`
+	Shifts                  []Shift                  `json:"shifts"`
+	Requests                []ShiftRequest           `json:"requests"`
+	Requirements            []DailyRequirement       `json:"requirements"`
+	PairRules               []StaffPairRule          `json:"pair_rules"`
+	DayOffRules             []DayOffRule             `json:"day_off_rules"`
+	RoleConstraints         []RoleConstraint         `json:"role_constraints"`
+	PublishedShifts         []PublishedShift         `json:"published_shifts"`
+	Versions                []ScheduleVersion        `json:"versions"`    // 中のシフトも含む
+	Swaps                   []ShiftSwap              `json:"swaps"`       // 状態の履歴も含む
+	OpenShifts              []OpenShift              `json:"open_shifts"` // 応募も含む
+	CallOuts                []CallOut                `json:"call_outs"`
+	NotificationPreferences []NotificationPreference `json:"notification_preferences"`
+}
+
+// RestoreResult: 復元の結果（テーブルごとの件数）
+type RestoreResult struct {
+	DryRun   bool           `json:"dry_run"`
+	Version  int            `json:"version"`  // 読み込んだバックアップの版
+	Restored map[string]int `json:"restored"` // 復元した件数
+	Skipped  map[string]int `json:"skipped"`  // 削除済みのスタッフ・期間を指していたため復元しなかった件数
+
+	// 復元したスタッフに紐づけ直せなかったアカウント（社員番号・名前で探す。スタッフの権限ならログインしても操作できない）
+	UnlinkedUsers []string `json:"unlinked_users,omitempty"`
+}
@@ -0,0 +1,21 @@
+package domain
+
+import "errors"
+
+// 業務ルール上のエラー（Handler側でステータスコードを切り替えるために使う）
+var (
+	ErrInvalidInput         = errors.New("入力内容が正しくありません")
+	ErrPeriodLocked         = errors.New("確定済みの期間のため変更できません（再オープンが必要です）")
+	ErrInvalidPeriodStatus  = errors.New("現在のステータスではこの操作はできません")
+	ErrPeriodOverlap        = errors.New("他の期間と日付が重なっています")
+	ErrNotFound             = errors.New("対象が見つかりません")
+	ErrRuleViolation        = errors.New("ルール違反があるため保存できません")
+	ErrInvalidSwapStatus    = errors.New("現在の交換申請の状態ではこの操作はできません")
+	ErrRequestClosed        = errors.New("休み希望の受付は締め切られています")
+	ErrRequestLimit         = errors.New("今月の休み希望の上限を超えています")
+	ErrInvalidRequestStatus = errors.New("現在の休み希望の状態ではこの操作はできません")
+	ErrOpenShiftClosed      = errors.New("この募集シフトは締め切られています")
+	ErrShiftChanged         = errors.New("シフトが他の操作で変更されています")
+	ErrUnauthorized         = errors.New("ログインが必要です")
+	ErrForbidden            = errors.New("この操作を行う権限がありません")
+)
@@ -0,0 +1,97 @@
+package domain
+
+import (
+	"fmt"
+	"time"
+)
+
+// Holidays: from〜to の日本の祝日（"2026-01-01" -> "元日"）
+// 祝日法の現行ルール（ハッピーマンデー・振替休日・国民の休日）で計算する。春分・秋分は1980〜2099年の近似式
+func Holidays(from string, to string) map[string]string {
+	result := make(map[string]string)
+	start, err1 := time.Parse("2006-01-02", from)
+	end, err2 := time.Parse("2006-01-02", to)
+	if err1 != nil || err2 != nil {
+		return result
+	}
+	for y := start.Year(); y <= end.Year(); y++ {
+		for date, name := range holidaysOf(y) {
+			if date >= from && date <= to {
+				result[date] = name
+			}
+		}
+	}
+	return result
+}
+
+// HolidayName: 祝日でなければ空
+func HolidayName(date string) string {
+	return Holidays(date, date)[date]
+}
+
+func holidaysOf(y int) map[string]string {
+	day := func(m time.Month, d int) time.Time { return time.Date(y, m, d, 0, 0, 0, 0, time.UTC) }
+	// nthMonday: m月の第n月曜日
+	nthMonday := func(m time.Month, n int) time.Time {
+		first := day(m, 1)
+		offset := (int(time.Monday) - int(first.Weekday()) + 7) % 7
+		return first.AddDate(0, 0, offset+7*(n-1))
+	}
+	base := y - 1980
+	vernal := int(20.8431+0.242194*float64(base)) - base/4
+	autumnal := int(23.2488+0.242194*float64(base)) - base/4
+
+	list := []struct {
+		date time.Time
+		name string
+	}{
+		{day(time.January, 1), "元日"},
+		{nthMonday(time.January, 2), "成人の日"},
+		{day(time.February, 11), "建国記念の日"},
+		{day(time.February, 23), "天皇誕生日"},
+		{day(time.March, vernal), "春分の日"},
+		{day(time.April, 29), "昭和の日"},
+		{day(time.May, 3), "憲法記念日"},
+		{day(time.May, 4), "みどりの日"},
+		{day(time.May, 5), "こどもの日"},
+		{nthMonday(time.July, 3), "海の日"},
+		{day(time.August, 11), "山の日"},
+		{nthMonday(time.September, 3), "敬老の日"},
+		{day(time.September, autumnal), "秋分の日"},
+		{nthMonday(time.October, 2), "スポーツの日"},
+		{day(time.November, 3), "文化の日"},
+		{day(time.November, 23), "勤労感謝の日"},
+	}
+
+	key := func(t time.Time) string { return t.Format("2006-01-02") }
+	holidays := make(map[string]string)
+	for _, h := range list {
+		holidays[key(h.date)] = h.name
+	}
+
+	// 国民の休日: 前日と翌日が祝日の平日
+	for _, h := range list {
+		next := h.date.AddDate(0, 0, 1)
+		if _, ok := holidays[key(next)]; ok {
+			continue
+		}
+		if _, ok := holidays[key(next.AddDate(0, 0, 1))]; ok && next.Weekday() != time.Sunday {
+			holidays[key(next)] = "国民の休日"
+		}
+	}
+	// 振替休日: 日曜の祝日の後の、最初の祝日でない日
+	for _, h := range list {
+		if h.date.Weekday() != time.Sunday {
+			continue
+		}
+		d := h.date.AddDate(0, 0, 1)
+		for {
+			if _, ok := holidays[key(d)]; !ok {
+				break
+			}
+			d = d.AddDate(0, 0, 1)
+		}
+		holidays[key(d)] = fmt.Sprintf("振替休日（%s）", h.name)
+	}
+	return holidays
+}
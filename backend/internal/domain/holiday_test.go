@@ -0,0 +1,107 @@
+package domain
+
+import "testing"
+
+func TestHolidays(t *testing.T) {
+	tests := []struct {
+		name string
+		from string
+		to   string
+		want map[string]string
+	}{
+		{
+			name: "ゴールデンウィークの振替休日（5/3 が日曜）",
+			from: "2026-05-01",
+			to:   "2026-05-31",
+			want: map[string]string{
+				"2026-05-03": "憲法記念日",
+				"2026-05-04": "みどりの日",
+				"2026-05-05": "こどもの日",
+				"2026-05-06": "振替休日（憲法記念日）",
+			},
+		},
+		{
+			name: "敬老の日と秋分の日に挟まれた国民の休日",
+			from: "2026-09-01",
+			to:   "2026-09-30",
+			want: map[string]string{
+				"2026-09-21": "敬老の日",
+				"2026-09-22": "国民の休日",
+				"2026-09-23": "秋分の日",
+			},
+		},
+		{
+			name: "ハッピーマンデー",
+			from: "2026-01-01",
+			to:   "2026-01-31",
+			want: map[string]string{
+				"2026-01-01": "元日",
+				"2026-01-12": "成人の日",
+			},
+		},
+		{
+			name: "春分の日",
+			from: "2026-03-01",
+			to:   "2026-03-31",
+			want: map[string]string{"2026-03-20": "春分の日"},
+		},
+		{
+			name: "日曜の祝日の翌日が振替休日",
+			from: "2024-02-01",
+			to:   "2024-02-29",
+			want: map[string]string{
+				"2024-02-11": "建国記念の日",
+				"2024-02-12": "振替休日（建国記念の日）",
+				"2024-02-23": "天皇誕生日",
+			},
+		},
+		{
+			name: "年をまたぐ範囲",
+			from: "2025-12-31",
+			to:   "2026-01-01",
+			want: map[string]string{"2026-01-01": "元日"},
+		},
+		{
+			name: "祝日の無い月",
+			from: "2026-06-01",
+			to:   "2026-06-30",
+			want: map[string]string{},
+		},
+		{
+			name: "日付の形式が正しくなければ空",
+			from: "2026/05/01",
+			to:   "2026-05-31",
+			want: map[string]string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Holidays(tt.from, tt.to)
+			if len(got) != len(tt.want) {
+				t.Errorf("Holidays(%s, %s) = %v, want %v", tt.from, tt.to, got, tt.want)
+				return
+			}
+			for date, name := range tt.want {
+				if got[date] != name {
+					t.Errorf("%s = %q, want %q", date, got[date], name)
+				}
+			}
+		})
+	}
+}
+
+func TestHolidayName(t *testing.T) {
+	tests := []struct {
+		date string
+		want string
+	}{
+		{"2026-11-03", "文化の日"},
+		{"2026-11-04", ""},
+		{"2026-08-11", "山の日"},
+	}
+	for _, tt := range tests {
+		if got := HolidayName(tt.date); got != tt.want {
+			t.Errorf("HolidayName(%s) = %q, want %q", tt.date, got, tt.want)
+		}
+	}
+}
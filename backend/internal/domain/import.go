@@ -0,0 +1,110 @@
+package domain
+
+import "strings"
+
+// ImportTable: 取り込みファイル（CSV・Excel）の中身。Header は見出し行
+type ImportTable struct {
+	Header []string
+	Rows   []ImportRow
+}
+
+// ImportRow: 見出しより下の1行（Line はファイル上の行番号、1始まり）
+type ImportRow struct {
+	Line   int
+	Values []string
+}
+
+// Column: 見出しの列番号（別名のどれかに一致する列、無ければ -1）
+func (t *ImportTable) Column(aliases ...string) int {
+	for i, h := range t.Header {
+		for _, alias := range aliases {
+			if strings.EqualFold(h, alias) {
+				return i
+			}
+		}
+	}
+	return -1
+}
+
+// Value: 列の値（列が無い・行が短いときは空）
+func (r ImportRow) Value(col int) string {
+	if col < 0 || col >= len(r.Values) {
+		return ""
+	}
+	return r.Values[col]
+}
+
+// ImportRowError: 取り込みファイルの行ごとのエラー
+type ImportRowError struct {
+	Line    int    `json:"line"`   // ファイル上の行番号
+	Column  string `json:"column"` // 見出し（行全体のエラーなら空）
+	Message string `json:"message"`
+}
+
+// 取り込みで行ごとに行うこと
+const (
+	ImportActionCreate = "create"
+	ImportActionUpdate = "update"
+)
+
+// StaffImportRow: スタッフ名簿の1行分の取り込み結果
+type StaffImportRow struct {
+	Line   int    `json:"line"`
+	Action string `json:"action"` // ImportActionCreate / ImportActionUpdate
+	Staff  Staff  `json:"staff"`
+	Before *Staff `json:"-"` // 更新前（監査ログ用）
+}
+
+// StaffImportResult: スタッフ名簿の取り込み結果
+// エラーが1件でもあれば何も保存しない（Applied = false）
+type StaffImportResult struct {
+	DryRun  bool             `json:"dry_run"`
+	Applied bool             `json:"applied"`
+	Created int              `json:"created"`
+	Updated int              `json:"updated"`
+	Rows    []StaffImportRow `json:"rows"`
+	Errors  []ImportRowError `json:"errors"`
+}
+
+// ImportSummary: 休み希望・必要人数の取り込み結果（共通部分）
+// エラーか食い違いが1件でもあれば何も保存しない（Applied = false）
+type ImportSummary struct {
+	DryRun     bool             `json:"dry_run"`
+	Applied    bool             `json:"applied"`
+	Created    int              `json:"created"`
+	Updated    int              `json:"updated"`
+	Skipped    int              `json:"skipped"`    // 重複のため取り込まなかった行
+	Errors     []ImportRowError `json:"errors"`     // 内容の誤り
+	Duplicates []ImportRowError `json:"duplicates"` // ファイル内の重複、登録済みと同じ内容（取り込まずに進める）
+	Conflicts  []ImportRowError `json:"conflicts"`  // 登録済みの内容と食い違う行
+}
+
+// Blocked: 保存できない（エラーか食い違いがある）
+func (s ImportSummary) Blocked() bool {
+	return len(s.Errors) > 0 || len(s.Conflicts) > 0
+}
+
+// RequestImportRow: 休み希望の1行分
+type RequestImportRow struct {
+	Line    int          `json:"line"`
+	Action  string       `json:"action"`
+	Request ShiftRequest `json:"request"`
+}
+
+type RequestImportResult struct {
+	ImportSummary
+	Rows []RequestImportRow `json:"rows"`
+}
+
+// RequirementImportRow: 必要人数の1行分
+type RequirementImportRow struct {
+	Line        int               `json:"line"`
+	Action      string            `json:"action"`
+	Requirement DailyRequirement  `json:"requirement"`
+	Before      *DailyRequirement `json:"-"` // 上書き前（監査ログ用）
+}
+
+type RequirementImportResult struct {
+	ImportSummary
+	Rows []RequirementImportRow `json:"rows"`
+}
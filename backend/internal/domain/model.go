@@ -1,20 +1,249 @@
 package domain
 
+import (
+	"fmt"
+	"time"
+)
+
 // Staff: スタッフ情報
 type Staff struct {
-	ID         uint   `gorm:"primaryKey" json:"id"`
-	Name       string `json:"name"`
-	IsLeader   bool   `json:"is_leader"`
-	HourlyWage int    `json:"hourly_wage"`
-	Roles      string `json:"roles"` // "Kitchen,Leader"
+	ID           uint   `gorm:"primaryKey" json:"id"`
+	EmployeeCode string `gorm:"index" json:"employee_code"` // 社員番号（一括取り込みで同じ人を探すのに使う、空でもよい）
+	Name         string `json:"name"`
+	IsLeader     bool   `json:"is_leader"`
+	HourlyWage   int    `json:"hourly_wage"`
+	Roles        string `json:"roles"` // "Kitchen,Leader"
+
+	EmploymentType string `json:"employment_type"` // EmploymentFullTime / EmploymentPartTime
+
+	MaxRequestDaysPerMonth int `json:"max_request_days_per_month"` // 月あたりの休み希望の上限（0なら無制限）
+
+	// 有給休暇の付与日数の計算に使う
+	HireDate             string  `json:"hire_date"`              // 入社日 (YYYY-MM-DD)
+	WeeklyScheduledDays  int     `json:"weekly_scheduled_days"`  // 週の所定労働日数（0ならフルタイムの5日）
+	WeeklyScheduledHours float64 `json:"weekly_scheduled_hours"` // 週の所定労働時間（30時間以上ならフルタイムと同じ付与日数）
+
+	// 代わりの人を探すときの上限
+	MaxWeeklyHours  float64 `json:"max_weekly_hours"`  // 週の労働時間の上限（0なら無制限）
+	AnnualIncomeCap int     `json:"annual_income_cap"` // 年収の上限（扶養の範囲など、0なら無制限）
 }
 
+// 雇用区分
+const (
+	EmploymentFullTime = "full_time" // 正社員・フルタイム
+	EmploymentPartTime = "part_time" // パート・アルバイト
+)
+
 // Shift: 確定したシフト
 type Shift struct {
 	ID        uint   `gorm:"primaryKey" json:"id"`
 	StaffID   int    `json:"staff_id"`
 	Date      string `json:"date"`
 	ShiftType int    `json:"shift_type"`
+	Locked    bool   `json:"locked"` // trueなら再生成でも変更しない（手修正の固定）
+}
+
+// ShiftFilter: シフトの絞り込み条件（空の項目では絞り込まない）
+type ShiftFilter struct {
+	From     string
+	To       string
+	StaffIDs []int
+}
+
+// 期間のステータス
+const (
+	PeriodDraft     = "draft"     // 作成中（スタッフには見えない）
+	PeriodPublished = "published" // 公開済み（スタッフは公開時点のシフトを見る）
+	PeriodLocked    = "locked"    // 確定（再オープンするまで変更不可）
+)
+
+// SchedulePeriod: シフトの作成期間
+type SchedulePeriod struct {
+	ID          uint       `gorm:"primaryKey" json:"id"`
+	StoreName   string     `json:"store_name"`
+	StartDate   string     `json:"start_date"`
+	EndDate     string     `json:"end_date"`
+	Status      string     `json:"status"`
+	PublishedAt *time.Time `json:"published_at"`
+
+	RequestDeadlineDays int `json:"request_deadline_days"` // 開始日のN日前で休み希望の受付を締め切る（0なら締切なし）
+}
+
+// RequestDeadline: 休み希望の締切日（この日まで受け付ける。締切が無ければ空）
+func (p SchedulePeriod) RequestDeadline() string {
+	if p.RequestDeadlineDays <= 0 {
+		return ""
+	}
+	start, err := time.Parse("2006-01-02", p.StartDate)
+	if err != nil {
+		return ""
+	}
+	return start.AddDate(0, 0, -p.RequestDeadlineDays).Format("2006-01-02")
+}
+
+// Days: 期間の日数（日付が不正なら0）
+func (p SchedulePeriod) Days() int {
+	start, err := time.Parse("2006-01-02", p.StartDate)
+	if err != nil {
+		return 0
+	}
+	end, err := time.Parse("2006-01-02", p.EndDate)
+	if err != nil {
+		return 0
+	}
+	return int(end.Sub(start).Hours()/24) + 1
+}
+
+// PublishedShift: 公開時点のシフトのコピー（公開後に手修正しても、再公開するまでスタッフの見え方は変わらない）
+// 再公開では作り直さずに差分だけ反映し、カレンダー連携で同じ予定として更新できるようにする
+type PublishedShift struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	PeriodID  uint   `gorm:"index" json:"period_id"`
+	StaffID   int    `gorm:"index" json:"staff_id"`
+	Date      string `json:"date"`
+	ShiftType int    `json:"shift_type"`
+
+	Sequence  int       `gorm:"default:0" json:"sequence"`      // 再公開で変わるたびに1増える（iCalendar の SEQUENCE）
+	Cancelled bool      `gorm:"default:false" json:"cancelled"` // 再公開で無くなったシフト
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// CalendarToken: カレンダー購読用のURLに埋め込む、スタッフごとの合言葉
+type CalendarToken struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	StaffID   int       `gorm:"uniqueIndex" json:"staff_id"`
+	Token     string    `gorm:"uniqueIndex" json:"token"`
+	CreatedAt time.Time `json:"created_at"`
+}
+
+// スナップショットの作成元
+const (
+	VersionSourceGenerate = "generate" // 自動生成・修正モード
+	VersionSourceEdit     = "edit"     // 手修正の保存
+	VersionSourceRollback = "rollback" // 過去バージョンへの巻き戻し
+)
+
+// ScheduleVersion: 期間のシフトのスナップショット（作成後は変更しない）
+type ScheduleVersion struct {
+	ID        uint           `gorm:"primaryKey" json:"id"`
+	PeriodID  uint           `gorm:"index" json:"period_id"`
+	Version   int            `json:"version"` // 期間ごとの連番
+	Source    string         `json:"source"`
+	Note      string         `json:"note"`
+	CreatedAt time.Time      `json:"created_at"`
+	Shifts    []VersionShift `gorm:"foreignKey:VersionID" json:"shifts,omitempty"`
+}
+
+// VersionShift: スナップショットに含まれる1件分のシフト
+type VersionShift struct {
+	ID        uint   `gorm:"primaryKey" json:"-"`
+	VersionID uint   `gorm:"index" json:"-"`
+	StaffID   int    `json:"staff_id"`
+	Date      string `json:"date"`
+	ShiftType int    `json:"shift_type"`
+	Locked    bool   `json:"locked"`
+}
+
+// Assignment: 日付とシフト区分の組
+type Assignment struct {
+	Date      string `json:"date"`
+	ShiftType int    `json:"shift_type"`
+}
+
+// MovedAssignment: 移動した割り当て（日付またはシフト区分が変わったもの）
+type MovedAssignment struct {
+	From Assignment `json:"from"`
+	To   Assignment `json:"to"`
+}
+
+// StaffDiff: スタッフ1人分のバージョン間の差分
+type StaffDiff struct {
+	StaffID int               `json:"staff_id"`
+	Added   []Assignment      `json:"added"`
+	Removed []Assignment      `json:"removed"`
+	Moved   []MovedAssignment `json:"moved"`
+}
+
+// ScheduleDiff: 2つのバージョンの差分
+type ScheduleDiff struct {
+	FromVersion int         `json:"from_version"`
+	ToVersion   int         `json:"to_version"`
+	Staff       []StaffDiff `json:"staff"`
+}
+
+// シフト交換の種類
+const (
+	SwapGive  = "give"  // 申請者のシフトを相手に譲る
+	SwapTrade = "trade" // 申請者と相手のシフトを入れ替える
+)
+
+// シフト交換の状態
+// proposed → accepted（相手が承諾）→ approved（店長が承認してシフトに反映）
+const (
+	SwapProposed  = "proposed"  // 相手の返事待ち
+	SwapAccepted  = "accepted"  // 店長の承認待ち
+	SwapApproved  = "approved"  // 承認済み（シフトに反映）
+	SwapDeclined  = "declined"  // 相手が断った
+	SwapRejected  = "rejected"  // 店長が却下した
+	SwapCancelled = "cancelled" // 申請者が取り下げた
+)
+
+// ShiftSwap: スタッフ間のシフト交換の申請
+type ShiftSwap struct {
+	ID            uint        `gorm:"primaryKey" json:"id"`
+	Type          string      `json:"type"`            // SwapGive / SwapTrade
+	RequesterID   int         `json:"requester_id"`    // 申請したスタッフ
+	TargetID      int         `json:"target_id"`       // 相手のスタッフ
+	ShiftID       uint        `json:"shift_id"`        // 申請者のシフト
+	TargetShiftID uint        `json:"target_shift_id"` // 相手のシフト（trade のときだけ）
+	Status        string      `json:"status"`
+	Note          string      `json:"note"`
+	CreatedAt     time.Time   `json:"created_at"`
+	UpdatedAt     time.Time   `json:"updated_at"`
+	History       []SwapEvent `gorm:"foreignKey:SwapID" json:"history,omitempty"`
+}
+
+// SwapEvent: 交換申請の状態の変化（追記のみ）
+type SwapEvent struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	SwapID     uint      `gorm:"index" json:"swap_id"`
+	FromStatus string    `json:"from_status"` // 作成時は空
+	ToStatus   string    `json:"to_status"`
+	Actor      string    `json:"actor"`
+	Comment    string    `json:"comment"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// 監査ログの変更元
+const (
+	AuditSourceGeneration = "generation" // 自動生成・修正モード
+	AuditSourceEdit       = "edit"       // カレンダー上のドラッグ&ドロップ
+	AuditSourceAPI        = "api"        // それ以外のAPI呼び出し
+)
+
+// AuditLog: 変更履歴（追記のみ。更新・削除はしない）
+type AuditLog struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Actor     string    `json:"actor"`
+	Source    string    `json:"source"`
+	Entity    string    `gorm:"index" json:"entity"` // "staff" / "shift" / "request" / "requirement"
+	EntityID  uint      `json:"entity_id"`
+	Action    string    `json:"action"`                  // "create" / "update" / "delete" / "generate" など
+	StaffID   int       `gorm:"index" json:"staff_id"`   // 関係するスタッフ（無ければ0）
+	Date      string    `gorm:"index" json:"date"`       // 関係する日付（無ければ空）
+	Before    string    `gorm:"type:text" json:"before"` // 変更前の値（JSON）
+	After     string    `gorm:"type:text" json:"after"`  // 変更後の値（JSON）
+	CreatedAt time.Time `gorm:"index" json:"created_at"`
+}
+
+// AuditFilter: 監査ログの検索条件（空の項目は条件にしない）
+type AuditFilter struct {
+	Entity   string
+	EntityID uint
+	StaffID  int
+	Date     string // 関係する日付
+	From     string // 変更日時の範囲（YYYY-MM-DD）
+	To       string
 }
 
 // ShiftRequest: 希望休
@@ -23,14 +252,116 @@ type ShiftRequest struct {
 	StaffID int    `json:"staff_id"`
 	Date    string `json:"date"`
 	Type    string `json:"type"`
+
+	// 承認フロー（このカラムが無かった頃の希望休は承認済みとして扱う）
+	Status     string     `gorm:"default:approved" json:"status"`
+	Note       string     `json:"note"`    // スタッフからの理由など
+	Comment    string     `json:"comment"` // 店長からのコメント
+	ReviewedBy string     `json:"reviewed_by"`
+	ReviewedAt *time.Time `json:"reviewed_at"`
+	CreatedAt  time.Time  `json:"created_at"`
 }
 
+// 希望休の種類
+const (
+	RequestTypeNG        = "NG"   // 終日休み希望
+	RequestTypePaidLeave = "PAID" // 有給休暇（休みにしたうえで、所定労働時間分を給与に含める）
+)
+
+// 希望休の状態（ソルバーに渡すのは承認済みだけ）
+const (
+	RequestPending  = "pending"  // 承認待ち
+	RequestApproved = "approved" // 承認済み
+	RequestRejected = "rejected" // 却下
+)
+
 // RoleConstraint: 役割ごとの必要人数ルール
 type RoleConstraint struct {
+	ID    uint   `gorm:"primaryKey" json:"id"`
 	Role  string `json:"role"`
 	Count int    `json:"count"`
 }
 
+// PaidLeaveGrant: 有給休暇の付与1回分
+type PaidLeaveGrant struct {
+	GrantDate string `json:"grant_date"`
+	ExpiresOn string `json:"expires_on"` // この日まで使える（付与から2年）
+	Days      int    `json:"days"`
+	Taken     int    `json:"taken"`
+	Remaining int    `json:"remaining"`
+	Expired   bool   `json:"expired"`
+}
+
+// PaidLeaveObligation: 年5日の取得義務（10日以上付与した場合、付与日から1年以内に5日取らせる）
+type PaidLeaveObligation struct {
+	GrantDate string `json:"grant_date"`
+	Deadline  string `json:"deadline"`
+	Taken     int    `json:"taken"`
+	Shortfall int    `json:"shortfall"`
+	Status    string `json:"status"` // ObligationMet / ObligationAtRisk / ObligationMissed
+}
+
+// 年5日の取得義務の状態
+const (
+	ObligationMet    = "met"     // 5日取得済み
+	ObligationAtRisk = "at_risk" // 期限前でまだ足りない
+	ObligationMissed = "missed"  // 期限を過ぎても足りなかった
+)
+
+// PaidLeaveBalance: スタッフの有給休暇の状況
+type PaidLeaveBalance struct {
+	StaffID     int                   `json:"staff_id"`
+	Name        string                `json:"name"`
+	AsOf        string                `json:"as_of"`
+	Remaining   int                   `json:"remaining"`   // 今使える残日数
+	Pending     int                   `json:"pending"`     // 承認待ちの申請日数
+	Expiring    int                   `json:"expiring"`    // 90日以内に失効する日数
+	NextExpiry  string                `json:"next_expiry"` // 次に失効する日（残りが無ければ空）
+	Unallocated int                   `json:"unallocated"` // 付与日数を超えて取得した日数
+	Grants      []PaidLeaveGrant      `json:"grants"`
+	Obligations []PaidLeaveObligation `json:"obligations"`
+	Warnings    []string              `json:"warnings"`
+	HoursPerDay float64               `json:"hours_per_day"` // 1日取得したときに支払う時間
+}
+
+// ShiftTemplate: シフト区分の定義（0 = 休み は含まない）
+type ShiftTemplate struct {
+	Type         int    `json:"type"`
+	Name         string `json:"name"`
+	Start        string `json:"start"` // "09:00"
+	End          string `json:"end"`   // "18:00"
+	BreakMinutes int    `json:"break_minutes"`
+	Color        string `json:"color"` // 表に出すときの背景色（RRGGBB）
+}
+
+// ShiftTemplates: 早番・遅番（Python側の shift_type 1, 2 と対応）
+var ShiftTemplates = []ShiftTemplate{
+	{Type: 1, Name: "早番", Start: "09:00", End: "18:00", BreakMinutes: 60, Color: "FFE699"},
+	{Type: 2, Name: "遅番", Start: "18:00", End: "23:00", BreakMinutes: 0, Color: "C6E0B4"},
+}
+
+// FindShiftTemplate: シフト区分から定義を探す
+func FindShiftTemplate(shiftType int) (ShiftTemplate, bool) {
+	for _, t := range ShiftTemplates {
+		if t.Type == shiftType {
+			return t, true
+		}
+	}
+	return ShiftTemplate{}, false
+}
+
+// Hours: 休憩を除いた労働時間
+func (t ShiftTemplate) Hours() float64 {
+	return float64(minutesOf(t.End)-minutesOf(t.Start)-t.BreakMinutes) / 60
+}
+
+// minutesOf: "09:30" -> 570
+func minutesOf(hhmm string) int {
+	var h, m int
+	fmt.Sscanf(hhmm, "%d:%d", &h, &m)
+	return h*60 + m
+}
+
 // DailyRequirement: その日の必要人数設定
 type DailyRequirement struct {
 	ID          uint   `gorm:"primaryKey" json:"id"`
@@ -39,18 +370,384 @@ type DailyRequirement struct {
 	EveningNeed int    `json:"evening_need"`
 }
 
+// ペアルールの種類
+const (
+	PairTogether = "together" // StaffIDが入る時は必ずPartnerIDも同じシフトに入る（研修中スタッフと指導役など）
+	PairApart    = "apart"    // 2人を同じシフトに入れない
+)
+
+// StaffPairRule: スタッフ同士の組み合わせルール
+type StaffPairRule struct {
+	ID        uint   `gorm:"primaryKey" json:"id"`
+	StaffID   int    `json:"staff_id"`
+	PartnerID int    `json:"partner_id"`
+	Type      string `json:"type"` // PairTogether / PairApart
+}
+
+// DayOffRule: 休日ルール（スタッフ個別 or 雇用区分ごと）
+// スタッフ個別のルールがあればそちらを優先する
+type DayOffRule struct {
+	ID                uint   `gorm:"primaryKey" json:"id"`
+	StaffID           int    `json:"staff_id"`            // 0なら雇用区分単位のルール
+	EmploymentType    string `json:"employment_type"`     // StaffIDが0のときの対象
+	MinWeeklyDaysOff  int    `json:"min_weekly_days_off"` // 暦週（日曜〜土曜）ごとの最低休日数
+	PreferConsecutive bool   `json:"prefer_consecutive"`  // 休みを連休にまとめたい（ソフト制約）
+}
+
+// 違反の重さ
+const (
+	ViolationHard = "hard" // ソルバーでも必ず守るルール（手修正では拒否する）
+	ViolationSoft = "soft" // できれば守りたいルール（警告のみ）
+)
+
+// Violation: ルール違反の内容
+type Violation struct {
+	Rule    string `json:"rule"`
+	Level   string `json:"level"`
+	StaffID int    `json:"staff_id"` // スタッフに関係しない違反（人数不足など）は0
+	Date    string `json:"date"`
+	Message string `json:"message"`
+}
+
+// ScheduleEvaluation: 保存済みシフトの評価結果（ルール違反とKPI）
+type ScheduleEvaluation struct {
+	From           string      `json:"from"`
+	To             string      `json:"to"`
+	Violations     []Violation `json:"violations"`
+	HardViolations int         `json:"hard_violations"`
+	SoftViolations int         `json:"soft_violations"`
+
+	Coverage           CoverageKPI `json:"coverage"`
+	RoleMinimum        RoleKPI     `json:"role_minimum"`
+	Requests           RequestKPI  `json:"requests"`
+	MaxConsecutiveDays int         `json:"max_consecutive_days"` // 全スタッフ中の最長連勤
+	TotalHours         float64     `json:"total_hours"`
+	PaidLeaveHours     float64     `json:"paid_leave_hours"`
+	LaborCost          int         `json:"labor_cost"` // 有給分を含む
+	Fairness           FairnessKPI `json:"fairness"`
+	Staff              []StaffKPI  `json:"staff"`
+}
+
+// CoverageKPI: 必要人数に対する充足状況（日付×シフト区分の合計）
+type CoverageKPI struct {
+	Required    int     `json:"required"`    // 必要人数の合計
+	Scheduled   int     `json:"scheduled"`   // 入っている人数の合計
+	Filled      int     `json:"filled"`      // 必要人数までで数えた人数（過剰分は除く）
+	Shortage    int     `json:"shortage"`    // 不足人数の合計
+	Overstaffed int     `json:"overstaffed"` // 過剰人数の合計
+	Rate        float64 `json:"rate"`        // Filled / Required（必要人数が0なら1）
+}
+
+// RoleKPI: 役割ごとの人数ルールを満たした日数
+type RoleKPI struct {
+	Checked int     `json:"checked"` // 日付×ルールの数
+	Met     int     `json:"met"`
+	Rate    float64 `json:"rate"`
+}
+
+// RequestKPI: 休み希望がどれだけ守られたか
+type RequestKPI struct {
+	Total    int     `json:"total"`
+	Honoured int     `json:"honoured"`
+	Rate     float64 `json:"rate"`
+}
+
+// FairnessKPI: スタッフ間の勤務量の差（最大 - 最小）
+type FairnessKPI struct {
+	MinDays     int     `json:"min_days"`
+	MaxDays     int     `json:"max_days"`
+	DaysSpread  int     `json:"days_spread"`
+	HoursSpread float64 `json:"hours_spread"`
+}
+
+// StaffKPI: スタッフごとの集計
+type StaffKPI struct {
+	StaffID            int     `json:"staff_id"`
+	Name               string  `json:"name"`
+	WorkDays           int     `json:"work_days"`
+	Hours              float64 `json:"hours"`
+	PaidLeaveDays      int     `json:"paid_leave_days"`
+	PaidLeaveHours     float64 `json:"paid_leave_hours"` // 有給で支払う時間（Hours には含めない）
+	Cost               int     `json:"cost"`             // 勤務分 + 有給分
+	MaxConsecutiveDays int     `json:"max_consecutive_days"`
+	Violations         int     `json:"violations"`
+}
+
+// 過不足の状態
+const (
+	CoverageShort = "short" // 不足
+	CoverageOK    = "ok"    // ちょうど
+	CoverageOver  = "over"  // 過剰
+)
+
+// CoverageDay: 1日分の配置状況（ヒートマップの1列）
+type CoverageDay struct {
+	Date    string         `json:"date"`
+	Weekday int            `json:"weekday"` // 0 = 日曜
+	Slots   []CoverageSlot `json:"slots"`   // ShiftTemplates と同じ順
+	Roles   []RoleCoverage `json:"roles"`   // 役割ごとの人数ルール（1日単位）
+}
+
+// CoverageSlot: 日付×シフト区分の必要人数と配置人数
+type CoverageSlot struct {
+	ShiftType int            `json:"shift_type"`
+	ShiftName string         `json:"shift_name"`
+	Required  int            `json:"required"`
+	Scheduled int            `json:"scheduled"`
+	Delta     int            `json:"delta"` // Scheduled - Required（マイナスなら不足）
+	Status    string         `json:"status"`
+	StaffIDs  []int          `json:"staff_ids"`
+	Roles     map[string]int `json:"roles"` // 役割ごとの配置人数
+}
+
+// RoleCoverage: 役割ごとの必要人数と配置人数
+type RoleCoverage struct {
+	Role      string `json:"role"`
+	Required  int    `json:"required"`
+	Scheduled int    `json:"scheduled"`
+	Delta     int    `json:"delta"`
+	Status    string `json:"status"`
+}
+
+// CoverageStatus: 過不足から状態を決める
+func CoverageStatus(delta int) string {
+	switch {
+	case delta < 0:
+		return CoverageShort
+	case delta > 0:
+		return CoverageOver
+	}
+	return CoverageOK
+}
+
 // ShiftInput: Pythonに渡すデータ
 type ShiftInput struct {
 	StaffList       []Staff            `json:"staff_list"`
 	Requests        []ShiftRequest     `json:"requests"`
 	RoleConstraints []RoleConstraint   `json:"role_constraints"`
 	Requirements    []DailyRequirement `json:"requirements"`
+	PairRules       []StaffPairRule    `json:"pair_rules"`
+	DayOffRules     []DayOffRule       `json:"day_off_rules"` // スタッフごとに解決済みのルール
+	History         []Shift            `json:"history"`       // start_dateより前の保存済みシフト（変更しない実績として扱う）
+	Fixed           []Shift            `json:"fixed"`         // 期間内のロック済みシフト（この割り当てのまま固定）
 	Days            int                `json:"days"`
 	StartDate       string             `json:"start_date"` // ★これを追加しました！
+	PeriodID        uint               `json:"period_id"`  // 指定した場合は期間の開始日・日数を使う
+
+	// 修正モード（急な欠勤などで途中から組み直す）
+	Mode            string  `json:"mode"`             // "" = 通常生成, GenerateModeRepair = 修正
+	RepairFrom      string  `json:"repair_from"`      // この日以降だけを組み直す
+	StabilityWeight int     `json:"stability_weight"` // 既存シフトを変えないことの重み（大きいほど変更が減る）
+	Hints           []Shift `json:"hints"`            // 今保存されているシフト（できるだけこのまま残す）
+
+	AllowOpenShifts bool `json:"allow_open_shifts"` // 人数が足りなくても INFEASIBLE にせず、埋まらない枠を募集シフトにする
+
+	// 追加の制約（指定しなければ使わない）
+	RestInterval bool `json:"rest_interval"` // 遅番の翌日に早番を入れない（前期間の最終日も含める）
+	Fairness     bool `json:"fairness"`      // 前期間を含めた出勤日数のスタッフ間の差を小さくする
+}
+
+// 生成モード
+const GenerateModeRepair = "repair"
+
+// ShiftChange: 修正モードで変わった割り当て（0 = 休み）
+type ShiftChange struct {
+	StaffID int    `json:"staff_id"`
+	Date    string `json:"date"`
+	Before  int    `json:"before"`
+	After   int    `json:"after"`
 }
 
 // ShiftResult: 計算結果
 type ShiftResult struct {
-	Status   string          `json:"status"`
-	Schedule map[int][]int   `json:"schedule"`
-}
\ No newline at end of file
+	Status   string         `json:"status"`
+	Schedule map[int][]int  `json:"schedule"`
+	Unfilled []UnfilledSlot `json:"unfilled"` // allow_open_shifts のときだけ
+}
+
+// UnfilledSlot: 生成で埋まらなかった枠
+type UnfilledSlot struct {
+	Date      string `json:"date"`
+	ShiftType int    `json:"shift_type"` // 役割の不足は1日単位なので0（どちらでも可）
+	Role      string `json:"role"`
+	Count     int    `json:"count"`
+}
+
+// 募集シフトの出どころ
+const (
+	OpenShiftFromGeneration = "generation" // 自動生成で埋まらなかった枠
+	OpenShiftFromDrop       = "drop"       // スタッフが手放したシフト
+	OpenShiftFromManual     = "manual"     // 店長が追加で募集
+)
+
+// 募集シフトの状態
+const (
+	OpenShiftOpen   = "open"   // 募集中
+	OpenShiftFilled = "filled" // 決まった（シフトを作成済み）
+	OpenShiftClosed = "closed" // 募集を取り下げた
+)
+
+// 募集シフトの決め方
+const (
+	ClaimFirstCome = "first_come" // 早い者勝ち（条件を満たせばその場で決まる）
+	ClaimPriority  = "priority"   // 応募を集めて、優先度の高い人に決める
+)
+
+// 応募の状態
+const (
+	ClaimPending = "pending"
+	ClaimWon     = "won"
+	ClaimLost    = "lost"
+)
+
+// OpenShift: 募集中のシフト
+type OpenShift struct {
+	ID        uint             `gorm:"primaryKey" json:"id"`
+	Date      string           `gorm:"index" json:"date"`
+	ShiftType int              `json:"shift_type"` // 0ならどちらのシフトでも可（応募時に選ぶ）
+	Role      string           `json:"role"`       // 必要な役割（空なら誰でも）
+	Source    string           `json:"source"`
+	Status    string           `json:"status"`
+	ClaimMode string           `json:"claim_mode"`
+	DroppedBy int              `json:"dropped_by"` // 手放したスタッフ（drop のとき）
+	FilledBy  int              `json:"filled_by"`
+	ShiftID   uint             `json:"shift_id"` // 手放されたシフト（決まるまで元の人のまま）、または決まったときに作成したシフト
+	Note      string           `json:"note"`
+	CreatedAt time.Time        `json:"created_at"`
+	FilledAt  *time.Time       `json:"filled_at"`
+	Claims    []OpenShiftClaim `gorm:"foreignKey:OpenShiftID" json:"claims,omitempty"`
+}
+
+// OpenShiftClaim: 募集シフトへの応募
+type OpenShiftClaim struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	OpenShiftID uint      `gorm:"index" json:"open_shift_id"`
+	StaffID     int       `json:"staff_id"`
+	ShiftType   int       `json:"shift_type"`
+	Status      string    `json:"status"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CallOut: 当日欠勤と、代わりに入った人の記録
+type CallOut struct {
+	ID            uint      `gorm:"primaryKey" json:"id"`
+	ShiftID       uint      `gorm:"index" json:"shift_id"`
+	Date          string    `gorm:"index" json:"date"`
+	ShiftType     int       `json:"shift_type"`
+	AbsentStaffID int       `json:"absent_staff_id"`
+	ReplacementID int       `json:"replacement_id"`
+	Reason        string    `json:"reason"`
+	Actor         string    `json:"actor"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// ReplacementCandidate: 欠勤の代わりに入れるかどうかの判定結果
+// Score は小さいほど優先（人件費と最近の出勤日数をそれぞれ候補内の最大値で割って半分ずつ足したもの）
+type ReplacementCandidate struct {
+	StaffID    int         `json:"staff_id"`
+	Name       string      `json:"name"`
+	Roles      string      `json:"roles"`
+	Eligible   bool        `json:"eligible"`
+	Reasons    []string    `json:"reasons"`     // 入れない理由
+	Violations []Violation `json:"violations"`  // 入った場合に新しく発生する違反（soft も含む）
+	Cost       int         `json:"cost"`        // このシフトの人件費
+	WorkDays   int         `json:"work_days"`   // 前後2週間の出勤日数
+	WeekHours  float64     `json:"week_hours"`  // このシフトを入れた週の労働時間
+	YearIncome int         `json:"year_income"` // このシフトを入れた年間の見込み収入
+	Score      float64     `json:"score"`
+}
+
+// Replacements: 欠勤するシフトと代わりの候補
+type Replacements struct {
+	Shift         Shift                  `json:"shift"`
+	RequiredRoles []string               `json:"required_roles"` // 欠勤で足りなくなる役割（候補はこれを持っている必要がある）
+	Candidates    []ReplacementCandidate `json:"candidates"`
+}
+
+// 通知のきっかけ
+const (
+	EventSchedulePublished = "schedule_published" // シフトが公開された
+	EventShiftChanged      = "shift_changed"      // 公開後にシフトが変わった
+	EventSwapApproved      = "swap_approved"      // シフト交換が承認された
+	EventRequestApproved   = "request_approved"   // 休み希望が承認された
+	EventRequestRejected   = "request_rejected"   // 休み希望が却下された
+)
+
+// 通知の送り先
+const (
+	ChannelEmail   = "email"
+	ChannelWebhook = "webhook"
+	ChannelFake    = "fake" // 送らずに記録するだけ（開発・テスト用）
+)
+
+// 通知の状態
+const (
+	NotificationPending = "pending" // 送信待ち（失敗して再送待ちも含む）
+	NotificationSending = "sending" // 送信処理が取り出して送っている途中
+	NotificationSent    = "sent"
+	NotificationFailed  = "failed" // 再送の上限に達した
+)
+
+// Notification: 送信待ちの通知（アウトボックス）
+type Notification struct {
+	ID            uint       `gorm:"primaryKey" json:"id"`
+	StaffID       int        `gorm:"index" json:"staff_id"`
+	Event         string     `json:"event"`
+	Channel       string     `json:"channel"`
+	Address       string     `json:"address"` // メールアドレス・Webhook の URL
+	Subject       string     `json:"subject"`
+	Body          string     `json:"body"`
+	Data          string     `json:"data"` // Webhook に載せる JSON
+	Status        string     `gorm:"index" json:"status"`
+	Attempts      int        `json:"attempts"`
+	NextAttemptAt time.Time  `gorm:"index" json:"next_attempt_at"`
+	LastError     string     `json:"last_error"`
+	CreatedAt     time.Time  `json:"created_at"`
+	SentAt        *time.Time `json:"sent_at"`
+}
+
+// NotificationPreference: スタッフごとの通知の受け取り方
+type NotificationPreference struct {
+	ID         uint      `gorm:"primaryKey" json:"id"`
+	StaffID    int       `gorm:"uniqueIndex" json:"staff_id"`
+	Channels   string    `json:"channels"` // "email,webhook"（空なら通知しない）
+	Events     string    `json:"events"`   // "shift_changed,swap_approved"（空ならすべて）
+	Email      string    `json:"email"`
+	WebhookURL string    `json:"webhook_url"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// ScheduleGrid: スタッフ×日付のシフト表（Excel・PDF の出力に使う）
+type ScheduleGrid struct {
+	From       string          `json:"from"`
+	To         string          `json:"to"`
+	StoreName  string          `json:"store_name"`
+	Templates  []ShiftTemplate `json:"templates"`
+	Days       []GridDay       `json:"days"`
+	Rows       []GridRow       `json:"rows"`
+	TotalHours float64         `json:"total_hours"`
+	TotalCost  int             `json:"total_cost"`
+}
+
+// GridDay: 表の1列（1日）
+type GridDay struct {
+	Date     string      `json:"date"`
+	Weekday  int         `json:"weekday"` // 0=日曜
+	Holiday  string      `json:"holiday"` // 祝日名（祝日でなければ空）
+	Counts   map[int]int `json:"counts"`  // シフト区分 -> 人数
+	Required map[int]int `json:"required"`
+	Total    int         `json:"total"`
+}
+
+// GridRow: 表の1行（1人）
+// Cells・Marks は Days と同じ並び。Cells はシフト区分（0なら休み）、Marks は休みの種類（"有" = 有給）
+type GridRow struct {
+	StaffID       int      `json:"staff_id"`
+	Name          string   `json:"name"`
+	Cells         []int    `json:"cells"`
+	Marks         []string `json:"marks"`
+	WorkDays      int      `json:"work_days"`
+	Hours         float64  `json:"hours"`
+	PaidLeaveDays int      `json:"paid_leave_days"`
+	Cost          int      `json:"cost"` // 有給分を含む
+}
@@ -0,0 +1,197 @@
+package export
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"smart-shift-scheduler/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+	"golang.org/x/text/runes"
+	"golang.org/x/text/transform"
+)
+
+// CSV の文字コード
+const (
+	EncodingUTF8BOM  = "utf-8-bom" // Excel で文字化けしないよう BOM を付ける
+	EncodingShiftJIS = "shift_jis" // CP932（Windows-31J）。古い Excel や給与計算の外部委託先向け
+)
+
+// CSVColumn: CSV に出せる列
+type CSVColumn struct {
+	Key    string
+	Header string
+	value  func(s domain.Shift, staff domain.Staff, t domain.ShiftTemplate) string
+}
+
+// DefaultCSVColumns: 列の指定が無いときの列（以前の出力と同じ）
+var DefaultCSVColumns = []string{"date", "staff_name", "shift_name", "time"}
+
+var csvColumns = []CSVColumn{
+	{Key: "date", Header: "日付", value: func(s domain.Shift, _ domain.Staff, _ domain.ShiftTemplate) string { return s.Date }},
+	{Key: "weekday", Header: "曜日", value: func(s domain.Shift, _ domain.Staff, _ domain.ShiftTemplate) string { return weekdayOf(s.Date) }},
+	{Key: "staff_id", Header: "スタッフID", value: func(s domain.Shift, _ domain.Staff, _ domain.ShiftTemplate) string { return strconv.Itoa(s.StaffID) }},
+	{Key: "staff_name", Header: "スタッフ名", value: func(_ domain.Shift, st domain.Staff, _ domain.ShiftTemplate) string { return st.Name }},
+	{Key: "roles", Header: "役割", value: func(_ domain.Shift, st domain.Staff, _ domain.ShiftTemplate) string { return st.Roles }},
+	{Key: "shift_type", Header: "シフト区分", value: func(s domain.Shift, _ domain.Staff, _ domain.ShiftTemplate) string { return strconv.Itoa(s.ShiftType) }},
+	{Key: "shift_name", Header: "シフト種別", value: func(_ domain.Shift, _ domain.Staff, t domain.ShiftTemplate) string { return t.Name }},
+	{Key: "time", Header: "時間", value: func(_ domain.Shift, _ domain.Staff, t domain.ShiftTemplate) string {
+		if t.Start == "" {
+			return ""
+		}
+		return t.Start + "-" + t.End
+	}},
+	{Key: "start", Header: "開始", value: func(_ domain.Shift, _ domain.Staff, t domain.ShiftTemplate) string { return t.Start }},
+	{Key: "end", Header: "終了", value: func(_ domain.Shift, _ domain.Staff, t domain.ShiftTemplate) string { return t.End }},
+	{Key: "break_minutes", Header: "休憩（分）", value: func(_ domain.Shift, _ domain.Staff, t domain.ShiftTemplate) string {
+		return strconv.Itoa(t.BreakMinutes)
+	}},
+	{Key: "hours", Header: "労働時間", value: func(_ domain.Shift, _ domain.Staff, t domain.ShiftTemplate) string {
+		return strconv.FormatFloat(t.Hours(), 'f', -1, 64)
+	}},
+	{Key: "hourly_wage", Header: "時給", value: func(_ domain.Shift, st domain.Staff, _ domain.ShiftTemplate) string {
+		return strconv.Itoa(st.HourlyWage)
+	}},
+	{Key: "cost", Header: "人件費", value: func(_ domain.Shift, st domain.Staff, t domain.ShiftTemplate) string {
+		return strconv.FormatFloat(t.Hours()*float64(st.HourlyWage), 'f', -1, 64)
+	}},
+	{Key: "locked", Header: "固定", value: func(s domain.Shift, _ domain.Staff, _ domain.ShiftTemplate) string {
+		if s.Locked {
+			return "1"
+		}
+		return ""
+	}},
+}
+
+// CSVColumns: 列のキーから列を選ぶ（空なら DefaultCSVColumns）
+func CSVColumns(keys []string) ([]CSVColumn, error) {
+	if len(keys) == 0 {
+		keys = DefaultCSVColumns
+	}
+	var columns []CSVColumn
+	for _, key := range keys {
+		found := false
+		for _, col := range csvColumns {
+			if col.Key == key {
+				columns = append(columns, col)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("%w: 列 %q はありません", domain.ErrInvalidInput, key)
+		}
+	}
+	return columns, nil
+}
+
+// csvFlushRows: この行数ごとに書き出す（大きな履歴でもバッファに溜めない）
+const csvFlushRows = 256
+
+// ShiftCSVWriter: シフトを1行ずつ CSV に書く
+type ShiftCSVWriter struct {
+	w         io.Writer
+	csv       *csv.Writer
+	encoder   io.WriteCloser // Shift_JIS のときだけ（Close で残りを書き出す）
+	encoding  string
+	columns   []CSVColumn
+	templates map[int]domain.ShiftTemplate
+	rows      int
+}
+
+// NewShiftCSVWriter: 文字コードと列を指定して作る（まだ何も書かない）
+func NewShiftCSVWriter(w io.Writer, enc string, columns []CSVColumn) (*ShiftCSVWriter, error) {
+	cw := &ShiftCSVWriter{w: w, columns: columns, templates: make(map[int]domain.ShiftTemplate)}
+	switch strings.ToLower(enc) {
+	case "", EncodingUTF8BOM, "utf-8", "utf8":
+		cw.encoding = EncodingUTF8BOM
+		cw.csv = csv.NewWriter(w)
+	case EncodingShiftJIS, "sjis", "cp932":
+		cw.encoding = EncodingShiftJIS
+		// Shift_JIS に無い文字（絵文字など）は途中で止めずに「?」にする
+		cw.encoder = transform.NewWriter(w, transform.Chain(runes.Map(toShiftJIS), japanese.ShiftJIS.NewEncoder()))
+		cw.csv = csv.NewWriter(cw.encoder)
+	default:
+		return nil, fmt.Errorf("%w: encoding は %s か %s を指定してください", domain.ErrInvalidInput, EncodingUTF8BOM, EncodingShiftJIS)
+	}
+	for _, t := range domain.ShiftTemplates {
+		cw.templates[t.Type] = t
+	}
+	return cw, nil
+}
+
+// ContentType: HTTP の Content-Type
+func (cw *ShiftCSVWriter) ContentType() string {
+	if cw.encoding == EncodingShiftJIS {
+		return "text/csv; charset=Shift_JIS"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// WriteHeader: BOM（UTF-8 のとき）と見出し行
+func (cw *ShiftCSVWriter) WriteHeader() error {
+	if cw.encoding == EncodingUTF8BOM {
+		if _, err := cw.w.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+			return err
+		}
+	}
+	header := make([]string, len(cw.columns))
+	for i, col := range cw.columns {
+		header[i] = col.Header
+	}
+	return cw.csv.Write(header)
+}
+
+// Write: シフト1件を1行にする
+func (cw *ShiftCSVWriter) Write(s domain.Shift, staff domain.Staff) error {
+	t := cw.templates[s.ShiftType]
+	record := make([]string, len(cw.columns))
+	for i, col := range cw.columns {
+		record[i] = col.value(s, staff, t)
+	}
+	if err := cw.csv.Write(record); err != nil {
+		return err
+	}
+	cw.rows++
+	if cw.rows%csvFlushRows == 0 {
+		cw.csv.Flush()
+		return cw.csv.Error()
+	}
+	return nil
+}
+
+// Close: 残りを書き出す
+func (cw *ShiftCSVWriter) Close() error {
+	cw.csv.Flush()
+	if err := cw.csv.Error(); err != nil {
+		return err
+	}
+	if cw.encoder != nil {
+		return cw.encoder.Close()
+	}
+	return nil
+}
+
+// toShiftJIS: Shift_JIS で表せない文字を「?」にする
+func toShiftJIS(r rune) rune {
+	if r < utf8.RuneSelf {
+		return r
+	}
+	if _, err := japanese.ShiftJIS.NewEncoder().String(string(r)); err != nil {
+		return '?'
+	}
+	return r
+}
+
+// weekdayOf: "2026-02-10" -> "火"
+func weekdayOf(date string) string {
+	t, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return ""
+	}
+	return weekdayNames[t.Weekday()]
+}
@@ -0,0 +1,446 @@
+package export
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"math"
+	"smart-shift-scheduler/internal/domain"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+)
+
+// PaperSize: 用紙の大きさ（横向き、単位は pt）
+type PaperSize struct {
+	Name   string
+	Width  float64
+	Height float64
+}
+
+var (
+	PaperA4 = PaperSize{Name: "A4", Width: 842, Height: 595}
+	PaperA3 = PaperSize{Name: "A3", Width: 1191, Height: 842}
+)
+
+// PaperSizeOf: "a4" / "a3" から用紙を選ぶ（空なら A4）
+func PaperSizeOf(name string) (PaperSize, bool) {
+	switch strings.ToLower(name) {
+	case "", "a4":
+		return PaperA4, true
+	case "a3":
+		return PaperA3, true
+	}
+	return PaperSize{}, false
+}
+
+// 日本語は PDF リーダー内蔵の平成角ゴシックを使う（フォントは埋め込まない）
+const (
+	pdfFont     = "HeiseiKakuGo-W5"
+	pdfEncoding = "UniJIS-UCS2-H"
+)
+
+// レイアウト（pt）
+const (
+	pdfMargin       = 28.0
+	pdfTitleHeight  = 48.0 // 店舗名・期間
+	pdfHeadHeight   = 28.0 // 日付の見出し（日付・曜日/祝日）
+	pdfRowHeight    = 18.0
+	pdfLegendHeight = 30.0
+	pdfNameWidth    = 110.0
+	pdfSumWidth     = 44.0
+	colorBlank      = "D9D9D9" // 期間外の曜日
+	colorBorder     = "999999"
+	colorMuted      = "595959"
+)
+
+// pdfPage: 1ページ分（1週間、人数が多ければ途中で次のページに分ける）
+type pdfPage struct {
+	first, last int // grid.Days の添字（last を含む）
+	rows        []domain.GridRow
+	continued   bool // 同じ週の2ページ目以降
+	totals      bool // 日ごとの人数を付ける（週の最後のページ）
+}
+
+// WriteSchedulePDF: スタッフ×日付のシフト表を印刷用 PDF（横向き）で書き出す
+// 1ページ1週間（日曜始まり）。列は曜日で揃え、期間外の曜日は灰色にする
+// 下に日ごとの人数とシフト時間の凡例、ヘッダーに店舗名を付ける
+func WriteSchedulePDF(w io.Writer, grid *domain.ScheduleGrid, paper PaperSize) error {
+	pages := paginate(grid, paper)
+	streams := make([][]byte, len(pages))
+	for i, p := range pages {
+		content := drawPage(grid, paper, p, i+1, len(pages))
+		stream, err := deflate(content)
+		if err != nil {
+			return err
+		}
+		streams[i] = stream
+	}
+
+	const (
+		catalogID = iota + 1
+		pagesID
+		fontID
+		cidFontID
+		descriptorID
+		infoID
+		firstPageID
+	)
+	pageID := func(i int) int { return firstPageID + i*2 }
+
+	var buf bytes.Buffer
+	offsets := make([]int, firstPageID+len(pages)*2)
+	object := func(id int, body string) {
+		offsets[id] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n%s\nendobj\n", id, body)
+	}
+
+	buf.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	object(catalogID, fmt.Sprintf("<< /Type /Catalog /Pages %d 0 R >>", pagesID))
+
+	kids := make([]string, len(pages))
+	for i := range pages {
+		kids[i] = fmt.Sprintf("%d 0 R", pageID(i))
+	}
+	object(pagesID, fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d /MediaBox [0 0 %s %s] >>",
+		strings.Join(kids, " "), len(pages), pdfNum(paper.Width), pdfNum(paper.Height)))
+
+	object(fontID, fmt.Sprintf("<< /Type /Font /Subtype /Type0 /BaseFont /%s-%s /Encoding /%s /DescendantFonts [%d 0 R] >>",
+		pdfFont, pdfEncoding, pdfEncoding, cidFontID))
+	// 英数字（CID 1-95）と半角カナ（CID 231-632）は半角幅、それ以外は全角幅
+	object(cidFontID, fmt.Sprintf("<< /Type /Font /Subtype /CIDFontType0 /BaseFont /%s "+
+		"/CIDSystemInfo << /Registry (Adobe) /Ordering (Japan1) /Supplement 2 >> "+
+		"/FontDescriptor %d 0 R /DW 1000 /W [1 95 500 231 632 500] >>", pdfFont, descriptorID))
+	object(descriptorID, fmt.Sprintf("<< /Type /FontDescriptor /FontName /%s /Flags 4 /FontBBox [-92 -250 1010 922] "+
+		"/ItalicAngle 0 /Ascent 752 /Descent -221 /CapHeight 737 /StemV 114 >>", pdfFont))
+	// 文書情報の文字列は BOM 付き UTF-16BE
+	object(infoID, fmt.Sprintf("<< /Title <FEFF%s /Producer (smart-shift-scheduler) >>",
+		pdfText(fmt.Sprintf("シフト表 %s〜%s", grid.From, grid.To))[1:]))
+
+	for i, stream := range streams {
+		object(pageID(i), fmt.Sprintf("<< /Type /Page /Parent %d 0 R /Resources << /Font << /F1 %d 0 R >> >> /Contents %d 0 R >>",
+			pagesID, fontID, pageID(i)+1))
+		offsets[pageID(i)+1] = buf.Len()
+		fmt.Fprintf(&buf, "%d 0 obj\n<< /Length %d /Filter /FlateDecode >>\nstream\n", pageID(i)+1, len(stream))
+		buf.Write(stream)
+		buf.WriteString("\nendstream\nendobj\n")
+	}
+
+	xref := buf.Len()
+	fmt.Fprintf(&buf, "xref\n0 %d\n0000000000 65535 f \n", len(offsets))
+	for _, offset := range offsets[1:] {
+		fmt.Fprintf(&buf, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&buf, "trailer\n<< /Size %d /Root %d 0 R /Info %d 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets), catalogID, infoID, xref)
+
+	_, err := w.Write(buf.Bytes())
+	return err
+}
+
+// paginate: 日曜始まりで週に分け、1ページに入りきらない人数は同じ週の次のページに回す
+func paginate(grid *domain.ScheduleGrid, paper PaperSize) []pdfPage {
+	footer := float64(len(grid.Templates)+1) * pdfRowHeight
+	perPage := int((paper.Height - pdfMargin*2 - pdfTitleHeight - pdfHeadHeight - pdfLegendHeight - footer) / pdfRowHeight)
+	if perPage < 1 {
+		perPage = 1
+	}
+
+	var pages []pdfPage
+	for _, week := range splitWeeks(grid.Days) {
+		rows := grid.Rows
+		for first := true; first || len(rows) > 0; first = false {
+			n := min(perPage, len(rows))
+			pages = append(pages, pdfPage{first: week[0], last: week[1], rows: rows[:n], continued: !first, totals: n == len(rows)})
+			rows = rows[n:]
+		}
+	}
+	return pages
+}
+
+// splitWeeks: 日付を日曜始まりの週に分ける（添字の範囲 [最初, 最後]）
+func splitWeeks(days []domain.GridDay) [][2]int {
+	var weeks [][2]int
+	for i, day := range days {
+		if i == 0 || day.Weekday == 0 {
+			weeks = append(weeks, [2]int{i, i})
+			continue
+		}
+		weeks[len(weeks)-1][1] = i
+	}
+	return weeks
+}
+
+// drawPage: 1ページ分の描画命令
+func drawPage(grid *domain.ScheduleGrid, paper PaperSize, p pdfPage, pageNo int, pageCount int) string {
+	c := &pdfCanvas{height: paper.Height}
+	days := grid.Days[p.first : p.last+1]
+	hours := make(map[int]float64)
+	for _, t := range grid.Templates {
+		hours[t.Type] = t.Hours()
+	}
+
+	// 見出し
+	store := grid.StoreName
+	if store == "" {
+		store = "シフト表"
+	} else {
+		store += " シフト表"
+	}
+	c.text(pdfMargin, pdfMargin+16, 16, store, "")
+	week := fmt.Sprintf("%s〜%s", longDate(days[0]), longDate(days[len(days)-1]))
+	if p.continued {
+		week += "（続き）"
+	}
+	c.text(pdfMargin, pdfMargin+36, 10, week, "")
+	c.textRight(paper.Width-pdfMargin, pdfMargin+16, 8, fmt.Sprintf("%d / %d", pageNo, pageCount), colorMuted)
+	c.textRight(paper.Width-pdfMargin, pdfMargin+36, 8, fmt.Sprintf("出力範囲 %s〜%s", grid.From, grid.To), colorMuted)
+
+	// 列の位置（日付の列は曜日で揃える）
+	dayWidth := (paper.Width - pdfMargin*2 - pdfNameWidth - pdfSumWidth*2) / 7
+	dayX := func(weekday int) float64 { return pdfMargin + pdfNameWidth + float64(weekday)*dayWidth }
+	sumX := dayX(7)
+	inWeek := make(map[int]int) // 曜日 -> grid.Days の添字
+	for i, day := range days {
+		inWeek[day.Weekday] = p.first + i
+	}
+
+	y := pdfMargin + pdfTitleHeight
+	c.cell(pdfMargin, y, pdfNameWidth, pdfHeadHeight, colorHeader, "スタッフ", 9, "")
+	for wd := 0; wd < 7; wd++ {
+		i, ok := inWeek[wd]
+		if !ok {
+			c.cell(dayX(wd), y, dayWidth, pdfHeadHeight, colorBlank, "", 0, "")
+			continue
+		}
+		day := grid.Days[i]
+		fill := dayColor(day)
+		if fill == "" {
+			fill = colorHeader
+		}
+		c.cell(dayX(wd), y, dayWidth, pdfHeadHeight, fill, "", 0, "")
+		c.textCenter(dayX(wd)+dayWidth/2, y+12, 9, shortDate(day.Date), "")
+		sub := "（" + weekdayNames[day.Weekday] + "）"
+		if day.Holiday != "" {
+			sub = fit(weekdayNames[day.Weekday]+" "+day.Holiday, 6.5, dayWidth-4)
+		}
+		c.textCenter(dayX(wd)+dayWidth/2, y+23, 6.5, sub, "")
+	}
+	c.cell(sumX, y, pdfSumWidth, pdfHeadHeight, colorHeader, "日数", 9, "")
+	c.cell(sumX+pdfSumWidth, y, pdfSumWidth, pdfHeadHeight, colorHeader, "時間", 9, "")
+	y += pdfHeadHeight
+
+	// スタッフごとの行（日数・時間はこの週の分）
+	for _, row := range p.rows {
+		c.cell(pdfMargin, y, pdfNameWidth, pdfRowHeight, "", "", 0, "")
+		c.text(pdfMargin+4, y+pdfRowHeight/2+3.2, 9, fit(row.Name, 9, pdfNameWidth-8), "")
+		workDays, workHours := 0, 0.0
+		for wd := 0; wd < 7; wd++ {
+			i, ok := inWeek[wd]
+			switch {
+			case !ok:
+				c.cell(dayX(wd), y, dayWidth, pdfRowHeight, colorBlank, "", 0, "")
+			case row.Cells[i] != 0:
+				workDays++
+				workHours += hours[row.Cells[i]]
+				c.cell(dayX(wd), y, dayWidth, pdfRowHeight, templateColor(grid.Templates, row.Cells[i]), shortName(grid.Templates, row.Cells[i]), 9, "")
+			default:
+				c.cell(dayX(wd), y, dayWidth, pdfRowHeight, dayColor(grid.Days[i]), row.Marks[i], 9, colorMuted)
+			}
+		}
+		c.cell(sumX, y, pdfSumWidth, pdfRowHeight, "", strconv.Itoa(workDays), 9, "")
+		c.cell(sumX+pdfSumWidth, y, pdfSumWidth, pdfRowHeight, "", strconv.FormatFloat(workHours, 'f', 1, 64), 9, "")
+		y += pdfRowHeight
+	}
+
+	// 日ごとの人数（足りない日は赤字で「人数/必要数」）
+	if p.totals {
+		for _, t := range grid.Templates {
+			c.cell(pdfMargin, y, pdfNameWidth, pdfRowHeight, colorHeader, t.Name+" 人数", 8, "")
+			for wd := 0; wd < 7; wd++ {
+				i, ok := inWeek[wd]
+				if !ok {
+					c.cell(dayX(wd), y, dayWidth, pdfRowHeight, colorBlank, "", 0, "")
+					continue
+				}
+				day := grid.Days[i]
+				label, color := strconv.Itoa(day.Counts[t.Type]), ""
+				if need := day.Required[t.Type]; need > 0 {
+					label += "/" + strconv.Itoa(need)
+					if day.Counts[t.Type] < need {
+						color = colorShortFont
+					}
+				}
+				c.cell(dayX(wd), y, dayWidth, pdfRowHeight, "", label, 8, color)
+			}
+			c.cell(sumX, y, pdfSumWidth*2, pdfRowHeight, "", "", 0, "")
+			y += pdfRowHeight
+		}
+
+		weekHours := 0.0
+		c.cell(pdfMargin, y, pdfNameWidth, pdfRowHeight, colorHeader, "合計", 8, "")
+		for wd := 0; wd < 7; wd++ {
+			i, ok := inWeek[wd]
+			if !ok {
+				c.cell(dayX(wd), y, dayWidth, pdfRowHeight, colorBlank, "", 0, "")
+				continue
+			}
+			c.cell(dayX(wd), y, dayWidth, pdfRowHeight, "", strconv.Itoa(grid.Days[i].Total), 8, "")
+			for _, row := range grid.Rows {
+				weekHours += hours[row.Cells[i]]
+			}
+		}
+		c.cell(sumX, y, pdfSumWidth, pdfRowHeight, "", "", 0, "")
+		c.cell(sumX+pdfSumWidth, y, pdfSumWidth, pdfRowHeight, "", strconv.FormatFloat(weekHours, 'f', 1, 64), 8, "")
+		y += pdfRowHeight
+	}
+
+	// 凡例
+	y += 14
+	x := pdfMargin
+	for _, t := range grid.Templates {
+		c.cell(x, y-8, 10, 10, t.Color, "", 0, "")
+		label := fmt.Sprintf("%s %s-%s", t.Name, t.Start, t.End)
+		if t.BreakMinutes > 0 {
+			label += fmt.Sprintf("（休憩%d分）", t.BreakMinutes)
+		}
+		c.text(x+14, y, 8, label, "")
+		x += 14 + textWidth(label, 8) + 16
+	}
+	note := "有 = 有給休暇　赤字 = 必要人数に不足"
+	c.text(x, y, 8, note, colorMuted)
+	if pageNo == pageCount {
+		c.textRight(paper.Width-pdfMargin, y, 8, fmt.Sprintf("期間計 %s時間 / 人件費 %s円",
+			strconv.FormatFloat(grid.TotalHours, 'f', 1, 64), thousands(grid.TotalCost)), "")
+	}
+	return c.b.String()
+}
+
+// templateColor: シフト区分の色
+func templateColor(templates []domain.ShiftTemplate, shiftType int) string {
+	for _, t := range templates {
+		if t.Type == shiftType {
+			return t.Color
+		}
+	}
+	return ""
+}
+
+// longDate: "2026-02-10" -> "2026/2/10（火）"
+func longDate(day domain.GridDay) string {
+	return fmt.Sprintf("%s/%s（%s）", day.Date[:4], shortDate(day.Date), weekdayNames[day.Weekday])
+}
+
+// thousands: 1234567 -> "1,234,567"
+func thousands(n int) string {
+	s := strconv.Itoa(n)
+	sign := ""
+	if n < 0 {
+		sign, s = "-", s[1:]
+	}
+	for i := len(s) - 3; i > 0; i -= 3 {
+		s = s[:i] + "," + s[i:]
+	}
+	return sign + s
+}
+
+// pdfCanvas: 左上を原点にした座標で描画命令を組み立てる（PDF は左下が原点）
+type pdfCanvas struct {
+	b      strings.Builder
+	height float64
+}
+
+// cell: 枠付きの四角（fill が空なら塗らない）。text は中央に置く
+func (c *pdfCanvas) cell(x, y, w, h float64, fill string, text string, size float64, color string) {
+	if fill != "" {
+		fmt.Fprintf(&c.b, "%s rg %s %s %s %s re f\n", pdfColor(fill), pdfNum(x), pdfNum(c.height-y-h), pdfNum(w), pdfNum(h))
+	}
+	fmt.Fprintf(&c.b, "0.5 w %s RG %s %s %s %s re S\n", pdfColor(colorBorder), pdfNum(x), pdfNum(c.height-y-h), pdfNum(w), pdfNum(h))
+	if text != "" {
+		c.textCenter(x+w/2, y+h/2+size*0.36, size, fit(text, size, w-4), color)
+	}
+}
+
+// text: y はベースライン
+func (c *pdfCanvas) text(x, y, size float64, s string, color string) {
+	if color == "" {
+		color = "000000"
+	}
+	fmt.Fprintf(&c.b, "BT %s rg /F1 %s Tf 1 0 0 1 %s %s Tm %s Tj ET\n", pdfColor(color), pdfNum(size), pdfNum(x), pdfNum(c.height-y), pdfText(s))
+}
+
+func (c *pdfCanvas) textCenter(cx, y, size float64, s string, color string) {
+	c.text(cx-textWidth(s, size)/2, y, size, s, color)
+}
+
+func (c *pdfCanvas) textRight(right, y, size float64, s string, color string) {
+	c.text(right-textWidth(s, size), y, size, s, color)
+}
+
+// textWidth: 文字幅の見積もり（英数字・半角カナは半角、それ以外は全角）
+func textWidth(s string, size float64) float64 {
+	width := 0.0
+	for _, r := range s {
+		if r < 0x80 || (r >= 0xFF61 && r <= 0xFF9F) {
+			width += 0.5
+		} else {
+			width += 1
+		}
+	}
+	return width * size
+}
+
+// fit: 幅に収まらなければ末尾を「…」にする
+func fit(s string, size float64, maxWidth float64) string {
+	if textWidth(s, size) <= maxWidth {
+		return s
+	}
+	for s != "" {
+		_, n := utf8.DecodeLastRuneInString(s)
+		s = s[:len(s)-n]
+		if textWidth(s+"…", size) <= maxWidth {
+			return s + "…"
+		}
+	}
+	return ""
+}
+
+// pdfText: 文字列を UCS-2（ビッグエンディアン）の16進文字列にする。BMP 外の文字は「?」
+func pdfText(s string) string {
+	var b strings.Builder
+	b.WriteString("<")
+	for _, r := range s {
+		if r > 0xFFFF || r < 0x20 {
+			r = '?'
+		}
+		fmt.Fprintf(&b, "%04X", r)
+	}
+	b.WriteString(">")
+	return b.String()
+}
+
+// pdfColor: "FFE699" -> "1 0.902 0.6"
+func pdfColor(hex string) string {
+	v, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 6 {
+		return "0 0 0"
+	}
+	return fmt.Sprintf("%s %s %s", pdfNum(float64(v>>16&0xFF)/255), pdfNum(float64(v>>8&0xFF)/255), pdfNum(float64(v&0xFF)/255))
+}
+
+// pdfNum: 小数3桁まで（末尾の0は省く）
+func pdfNum(v float64) string {
+	return strconv.FormatFloat(math.Round(v*1000)/1000, 'f', -1, 64)
+}
+
+func deflate(content string) ([]byte, error) {
+	var buf bytes.Buffer
+	zw := zlib.NewWriter(&buf)
+	if _, err := io.WriteString(zw, content); err != nil {
+		return nil, err
+	}
+	if err := zw.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,409 @@
+// Package export: シフト表をファイル形式（Excel・PDF など）に書き出す
+package export
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"smart-shift-scheduler/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// 表の配色（RRGGBB）
+const (
+	colorSaturday  = "DDEBF7" // 土曜
+	colorHoliday   = "FCE4D6" // 日曜・祝日
+	colorHeader    = "F2F2F2"
+	colorShortFont = "C00000" // 人数が足りない日
+)
+
+var weekdayNames = []string{"日", "月", "火", "水", "木", "金", "土"}
+
+// WriteScheduleXLSX: スタッフ×日付のシフト表を .xlsx で書き出す
+// 行はスタッフ、列は日付。セルはシフトの頭文字（早・遅）を区分の色で塗り、土日祝の列に色を付ける
+// 下に日ごとの人数、右に人ごとの出勤日数・時間・有給・人件費の合計を付ける
+func WriteScheduleXLSX(w io.Writer, grid *domain.ScheduleGrid) error {
+	sheet, styles := buildSheet(grid)
+
+	files := []struct {
+		name string
+		body string
+	}{
+		{"[Content_Types].xml", xmlHeader + `<Types xmlns="http://schemas.openxmlformats.org/package/2006/content-types">` +
+			`<Default Extension="rels" ContentType="application/vnd.openxmlformats-package.relationships+xml"/>` +
+			`<Default Extension="xml" ContentType="application/xml"/>` +
+			`<Override PartName="/xl/workbook.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.sheet.main+xml"/>` +
+			`<Override PartName="/xl/worksheets/sheet1.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.worksheet+xml"/>` +
+			`<Override PartName="/xl/styles.xml" ContentType="application/vnd.openxmlformats-officedocument.spreadsheetml.styles+xml"/>` +
+			`</Types>`},
+		{"_rels/.rels", xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/officeDocument" Target="xl/workbook.xml"/>` +
+			`</Relationships>`},
+		{"xl/workbook.xml", xmlHeader + `<workbook xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">` +
+			`<sheets><sheet name="シフト表" sheetId="1" r:id="rId1"/></sheets></workbook>`},
+		{"xl/_rels/workbook.xml.rels", xmlHeader + `<Relationships xmlns="http://schemas.openxmlformats.org/package/2006/relationships">` +
+			`<Relationship Id="rId1" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/worksheet" Target="worksheets/sheet1.xml"/>` +
+			`<Relationship Id="rId2" Type="http://schemas.openxmlformats.org/officeDocument/2006/relationships/styles" Target="styles.xml"/>` +
+			`</Relationships>`},
+		{"xl/styles.xml", styles.xml()},
+		{"xl/worksheets/sheet1.xml", sheet},
+	}
+
+	zw := zip.NewWriter(w)
+	for _, f := range files {
+		fw, err := zw.Create(f.name)
+		if err != nil {
+			return err
+		}
+		if _, err := io.WriteString(fw, f.body); err != nil {
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+const xmlHeader = `<?xml version="1.0" encoding="UTF-8" standalone="yes"?>` + "\n"
+
+// buildSheet: シートの XML と、使ったセルの書式
+func buildSheet(grid *domain.ScheduleGrid) (string, *xlsxStyles) {
+	styles := newXLSXStyles()
+	days := len(grid.Days)
+	// 列: A=名前, B〜=日付, その後に合計4列
+	totalCol := days + 1
+
+	title := styles.add(xlsxStyle{bold: true, size: 14})
+	header := styles.add(xlsxStyle{bold: true, fill: colorHeader, border: true, center: true})
+	nameCell := styles.add(xlsxStyle{border: true})
+	labelCell := styles.add(xlsxStyle{bold: true, fill: colorHeader, border: true})
+	number := styles.add(xlsxStyle{border: true, center: true})
+	hours := styles.add(xlsxStyle{border: true, numFmt: 164})
+	money := styles.add(xlsxStyle{border: true, numFmt: 3})
+	colorOf := make(map[int]string)
+	for _, t := range grid.Templates {
+		colorOf[t.Type] = t.Color
+	}
+	// dayStyle: 列（日付）の色。シフトの色が優先
+	dayStyle := func(day domain.GridDay, fill string, bold bool, font string) int {
+		if fill == "" {
+			fill = dayColor(day)
+		}
+		return styles.add(xlsxStyle{fill: fill, bold: bold, border: true, center: true, font: font})
+	}
+
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<worksheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main" xmlns:r="http://schemas.openxmlformats.org/officeDocument/2006/relationships">`)
+	b.WriteString(`<sheetPr><pageSetUpPr fitToPage="1"/></sheetPr>`)
+	b.WriteString(`<sheetViews><sheetView workbookViewId="0"><pane xSplit="1" ySplit="3" topLeftCell="B4" activePane="bottomRight" state="frozen"/></sheetView></sheetViews>`)
+	b.WriteString(`<cols>`)
+	fmt.Fprintf(&b, `<col min="1" max="1" width="14" customWidth="1"/>`)
+	if days > 0 {
+		fmt.Fprintf(&b, `<col min="2" max="%d" width="4.5" customWidth="1"/>`, days+1)
+	}
+	fmt.Fprintf(&b, `<col min="%d" max="%d" width="8" customWidth="1"/>`, totalCol+1, totalCol+3)
+	fmt.Fprintf(&b, `<col min="%d" max="%d" width="11" customWidth="1"/>`, totalCol+4, totalCol+4)
+	b.WriteString(`</cols><sheetData>`)
+
+	row := 0
+	newRow := func() *xlsxRow {
+		row++
+		return &xlsxRow{b: &b, row: row}
+	}
+
+	// 1行目: タイトル
+	r := newRow()
+	r.str(0, strings.TrimSpace(grid.StoreName+" シフト表 "+grid.From+"〜"+grid.To), title)
+	r.end()
+
+	// 2・3行目: 日付と曜日
+	r = newRow()
+	r.str(0, "スタッフ", header)
+	for i, day := range grid.Days {
+		r.str(i+1, shortDate(day.Date), dayStyle(day, "", true, ""))
+	}
+	for i, label := range []string{"出勤日数", "時間", "有給", "人件費"} {
+		r.str(totalCol+i, label, header)
+	}
+	r.end()
+	r = newRow()
+	r.str(0, "", header)
+	for i, day := range grid.Days {
+		label := weekdayNames[day.Weekday]
+		if day.Holiday != "" {
+			label += "祝"
+		}
+		r.str(i+1, label, dayStyle(day, "", true, ""))
+	}
+	for i := 0; i < 4; i++ {
+		r.str(totalCol+i, "", header)
+	}
+	r.end()
+
+	// スタッフごとの行
+	for _, staff := range grid.Rows {
+		r = newRow()
+		r.str(0, staff.Name, nameCell)
+		for i, day := range grid.Days {
+			if t := staff.Cells[i]; t != 0 {
+				r.str(i+1, shortName(grid.Templates, t), dayStyle(day, colorOf[t], false, ""))
+			} else {
+				r.str(i+1, staff.Marks[i], dayStyle(day, "", false, ""))
+			}
+		}
+		r.num(totalCol, float64(staff.WorkDays), number)
+		r.num(totalCol+1, staff.Hours, hours)
+		r.num(totalCol+2, float64(staff.PaidLeaveDays), number)
+		r.num(totalCol+3, float64(staff.Cost), money)
+		r.end()
+	}
+
+	// 日ごとの人数（必要人数に足りない日は赤字）
+	for _, t := range grid.Templates {
+		r = newRow()
+		r.str(0, t.Name+" 人数", labelCell)
+		for i, day := range grid.Days {
+			font := ""
+			if day.Counts[t.Type] < day.Required[t.Type] {
+				font = colorShortFont
+			}
+			r.num(i+1, float64(day.Counts[t.Type]), dayStyle(day, "", false, font))
+		}
+		r.end()
+	}
+	r = newRow()
+	r.str(0, "合計 人数", labelCell)
+	for i, day := range grid.Days {
+		r.num(i+1, float64(day.Total), dayStyle(day, "", true, ""))
+	}
+	r.str(totalCol, "合計", labelCell)
+	r.num(totalCol+1, grid.TotalHours, hours)
+	r.str(totalCol+2, "", labelCell)
+	r.num(totalCol+3, float64(grid.TotalCost), money)
+	r.end()
+
+	// 凡例
+	newRow().end()
+	for _, t := range grid.Templates {
+		r = newRow()
+		r.str(0, t.Name, styles.add(xlsxStyle{fill: t.Color, border: true}))
+		r.str(1, fmt.Sprintf("%s %s-%s 休憩%d分", shortName(grid.Templates, t.Type), t.Start, t.End, t.BreakMinutes), 0)
+		r.end()
+	}
+	r = newRow()
+	r.str(0, "有", nameCell)
+	r.str(1, "有給休暇", 0)
+	r.end()
+	r = newRow()
+	r.str(0, "", styles.add(xlsxStyle{fill: colorSaturday, border: true}))
+	r.str(1, "土曜", 0)
+	r.end()
+	r = newRow()
+	r.str(0, "", styles.add(xlsxStyle{fill: colorHoliday, border: true}))
+	r.str(1, "日曜・祝日", 0)
+	r.end()
+
+	b.WriteString(`</sheetData>`)
+	b.WriteString(`<pageMargins left="0.4" right="0.4" top="0.5" bottom="0.5" header="0.3" footer="0.3"/>`)
+	b.WriteString(`<pageSetup paperSize="9" orientation="landscape" fitToWidth="1" fitToHeight="0"/>`)
+	b.WriteString(`</worksheet>`)
+	return b.String(), styles
+}
+
+// dayColor: 土曜は青、日曜・祝日は赤
+func dayColor(day domain.GridDay) string {
+	switch {
+	case day.Holiday != "" || day.Weekday == 0:
+		return colorHoliday
+	case day.Weekday == 6:
+		return colorSaturday
+	}
+	return ""
+}
+
+// shortDate: "2026-02-10" -> "2/10"
+func shortDate(date string) string {
+	if len(date) != 10 {
+		return date
+	}
+	m, _ := strconv.Atoi(date[5:7])
+	d, _ := strconv.Atoi(date[8:10])
+	return fmt.Sprintf("%d/%d", m, d)
+}
+
+// shortName: シフト区分の頭文字（早番 -> 早）
+func shortName(templates []domain.ShiftTemplate, shiftType int) string {
+	for _, t := range templates {
+		if t.Type == shiftType {
+			for _, r := range t.Name {
+				return string(r)
+			}
+		}
+	}
+	return strconv.Itoa(shiftType)
+}
+
+// xlsxRow: 1行分のセルを書く（列は0始まり）
+type xlsxRow struct {
+	b       *strings.Builder
+	row     int
+	started bool
+}
+
+func (r *xlsxRow) start() {
+	if !r.started {
+		fmt.Fprintf(r.b, `<row r="%d">`, r.row)
+		r.started = true
+	}
+}
+
+func (r *xlsxRow) str(col int, value string, style int) {
+	r.start()
+	fmt.Fprintf(r.b, `<c r="%s%d" s="%d" t="inlineStr"><is><t>`, columnName(col), r.row, style)
+	xml.EscapeText(r.b, []byte(value))
+	r.b.WriteString(`</t></is></c>`)
+}
+
+func (r *xlsxRow) num(col int, value float64, style int) {
+	r.start()
+	fmt.Fprintf(r.b, `<c r="%s%d" s="%d"><v>%s</v></c>`, columnName(col), r.row, style, strconv.FormatFloat(value, 'f', -1, 64))
+}
+
+func (r *xlsxRow) end() {
+	if !r.started {
+		fmt.Fprintf(r.b, `<row r="%d">`, r.row)
+	}
+	r.b.WriteString(`</row>`)
+}
+
+// columnName: 0 -> A, 25 -> Z, 26 -> AA
+func columnName(col int) string {
+	name := ""
+	for col++; col > 0; col = (col - 1) / 26 {
+		name = string(rune('A'+(col-1)%26)) + name
+	}
+	return name
+}
+
+// xlsxStyle: セルの書式（同じ組み合わせは1つの cellXfs にまとめる）
+type xlsxStyle struct {
+	fill   string // 背景色（空なら無し）
+	font   string // 文字色（空なら黒）
+	bold   bool
+	size   int // 文字サイズ（0なら10）
+	numFmt int // 0=標準, 3=#,##0, 164=0.0
+	border bool
+	center bool
+}
+
+type xlsxStyles struct {
+	list  []xlsxStyle
+	index map[xlsxStyle]int
+}
+
+func newXLSXStyles() *xlsxStyles {
+	s := &xlsxStyles{index: make(map[xlsxStyle]int)}
+	s.add(xlsxStyle{}) // 0番は標準
+	return s
+}
+
+func (s *xlsxStyles) add(style xlsxStyle) int {
+	if i, ok := s.index[style]; ok {
+		return i
+	}
+	s.index[style] = len(s.list)
+	s.list = append(s.list, style)
+	return len(s.list) - 1
+}
+
+// xml: styles.xml（フォント・塗り・罫線は使っているものだけ並べる）
+func (s *xlsxStyles) xml() string {
+	type fontKey struct {
+		color string
+		bold  bool
+		size  int
+	}
+	fonts := []fontKey{{size: 10}}
+	fontIndex := map[fontKey]int{{size: 10}: 0}
+	fills := []string{"", "gray125"}
+	fillIndex := map[string]int{}
+
+	var xfs bytes.Buffer
+	for _, st := range s.list {
+		size := st.size
+		if size == 0 {
+			size = 10
+		}
+		fk := fontKey{color: st.font, bold: st.bold, size: size}
+		fi, ok := fontIndex[fk]
+		if !ok {
+			fi = len(fonts)
+			fontIndex[fk] = fi
+			fonts = append(fonts, fk)
+		}
+		fill := 0
+		if st.fill != "" {
+			if i, ok := fillIndex[st.fill]; ok {
+				fill = i
+			} else {
+				fill = len(fills)
+				fillIndex[st.fill] = fill
+				fills = append(fills, st.fill)
+			}
+		}
+		border := 0
+		if st.border {
+			border = 1
+		}
+		fmt.Fprintf(&xfs, `<xf numFmtId="%d" fontId="%d" fillId="%d" borderId="%d" xfId="0"`, st.numFmt, fi, fill, border)
+		if st.numFmt != 0 {
+			xfs.WriteString(` applyNumberFormat="1"`)
+		}
+		xfs.WriteString(` applyFont="1" applyFill="1" applyBorder="1"`)
+		if st.center {
+			xfs.WriteString(` applyAlignment="1"><alignment horizontal="center" vertical="center"/></xf>`)
+		} else {
+			xfs.WriteString(`/>`)
+		}
+	}
+
+	var b strings.Builder
+	b.WriteString(xmlHeader)
+	b.WriteString(`<styleSheet xmlns="http://schemas.openxmlformats.org/spreadsheetml/2006/main">`)
+	b.WriteString(`<numFmts count="1"><numFmt numFmtId="164" formatCode="0.0"/></numFmts>`)
+	fmt.Fprintf(&b, `<fonts count="%d">`, len(fonts))
+	for _, f := range fonts {
+		b.WriteString(`<font>`)
+		if f.bold {
+			b.WriteString(`<b/>`)
+		}
+		fmt.Fprintf(&b, `<sz val="%d"/>`, f.size)
+		if f.color != "" {
+			fmt.Fprintf(&b, `<color rgb="FF%s"/>`, f.color)
+		}
+		b.WriteString(`<name val="Meiryo UI"/><family val="3"/><charset val="128"/></font>`)
+	}
+	b.WriteString(`</fonts>`)
+	fmt.Fprintf(&b, `<fills count="%d">`, len(fills))
+	for i, f := range fills {
+		switch i {
+		case 0:
+			b.WriteString(`<fill><patternFill patternType="none"/></fill>`)
+		case 1:
+			b.WriteString(`<fill><patternFill patternType="gray125"/></fill>`)
+		default:
+			fmt.Fprintf(&b, `<fill><patternFill patternType="solid"><fgColor rgb="FF%s"/><bgColor indexed="64"/></patternFill></fill>`, f)
+		}
+	}
+	b.WriteString(`</fills>`)
+	b.WriteString(`<borders count="2"><border><left/><right/><top/><bottom/><diagonal/></border>`)
+	b.WriteString(`<border><left style="thin"><color rgb="FFBFBFBF"/></left><right style="thin"><color rgb="FFBFBFBF"/></right>` +
+		`<top style="thin"><color rgb="FFBFBFBF"/></top><bottom style="thin"><color rgb="FFBFBFBF"/></bottom><diagonal/></border></borders>`)
+	b.WriteString(`<cellStyleXfs count="1"><xf numFmtId="0" fontId="0" fillId="0" borderId="0"/></cellStyleXfs>`)
+	fmt.Fprintf(&b, `<cellXfs count="%d">%s</cellXfs>`, len(s.list), xfs.String())
+	b.WriteString(`<cellStyles count="1"><cellStyle name="Normal" xfId="0" builtinId="0"/></cellStyles>`)
+	b.WriteString(`</styleSheet>`)
+	return b.String()
+}
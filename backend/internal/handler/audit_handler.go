@@ -0,0 +1,79 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type AuditHandler struct {
+	usecase *usecase.AuditUsecase
+}
+
+func NewAuditHandler(u *usecase.AuditUsecase) *AuditHandler {
+	return &AuditHandler{usecase: u}
+}
+
+// List: 監査ログの検索 (?entity=shift&entity_id=&staff_id=&date=&from=&to=)
+func (h *AuditHandler) List(c *gin.Context) {
+	filter := domain.AuditFilter{
+		Entity: c.Query("entity"),
+		Date:   c.Query("date"),
+		From:   c.Query("from"),
+		To:     c.Query("to"),
+	}
+	if v := c.Query("entity_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid entity_id"})
+			return
+		}
+		filter.EntityID = uint(id)
+	}
+	if v := c.Query("staff_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff_id"})
+			return
+		}
+		filter.StaffID = id
+	}
+
+	logs, err := h.usecase.Search(filter)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, logs)
+}
+
+// actorOf: 操作した人（ログイン中のユーザー名）
+func actorOf(c *gin.Context) string {
+	if user := currentUser(c); user != nil {
+		return user.Username
+	}
+	return "anonymous"
+}
+
+// sourceOf: 変更元（カレンダー画面からは X-Change-Source: edit を付けて送る）
+func sourceOf(c *gin.Context) string {
+	if c.GetHeader("X-Change-Source") == domain.AuditSourceEdit {
+		return domain.AuditSourceEdit
+	}
+	return domain.AuditSourceAPI
+}
+
+// recordAudit: 監査ログを残す（失敗してもリクエスト自体は成功扱いにし、ログだけ出す）
+func recordAudit(c *gin.Context, audit *usecase.AuditUsecase, entry domain.AuditLog, before interface{}, after interface{}) {
+	entry.Actor = actorOf(c)
+	if entry.Source == "" {
+		entry.Source = sourceOf(c)
+	}
+	if err := audit.Record(entry, before, after); err != nil {
+		log.Printf("監査ログの保存に失敗しました: %v", err)
+	}
+}
@@ -0,0 +1,212 @@
+package handler
+
+import (
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	sessionCookie = "session" // ブラウザはクッキー、それ以外は Authorization: Bearer <token>
+	userKey       = "user"    // gin.Context に入れるログイン中のユーザー
+)
+
+type AuthHandler struct {
+	usecase *usecase.AuthUsecase
+	audit   *usecase.AuditUsecase
+}
+
+func NewAuthHandler(u *usecase.AuthUsecase, audit *usecase.AuditUsecase) *AuthHandler {
+	return &AuthHandler{usecase: u, audit: audit}
+}
+
+// Authenticate: ログインしていなければ 401 で止めるミドルウェア
+func (h *AuthHandler) Authenticate(c *gin.Context) {
+	user, err := h.usecase.Authenticate(tokenOf(c))
+	if err != nil {
+		c.AbortWithStatusJSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.Set(userKey, user)
+	c.Next()
+}
+
+// RequireRole: role 以上の権限が無ければ 403 で止めるミドルウェア（Authenticate の後に置く）
+func RequireRole(role string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if user := currentUser(c); user == nil || !user.HasRole(role) {
+			c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": domain.ErrForbidden.Error()})
+			return
+		}
+		c.Next()
+	}
+}
+
+// RequireSelf: /staff/:id/... を本人（か店長以上）だけに許すミドルウェア
+func RequireSelf(param string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param(param))
+		if err != nil {
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+			return
+		}
+		if !allowedFor(c, id) {
+			return
+		}
+		c.Next()
+	}
+}
+
+// Login: ログイン {"username": "...", "password": "..."}（クッキーにもセッションを入れる）
+func (h *AuthHandler) Login(c *gin.Context) {
+	var body struct {
+		Username string `json:"username"`
+		Password string `json:"password"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	result, err := h.usecase.Login(body.Username, body.Password)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	setSessionCookie(c, result)
+	c.JSON(http.StatusOK, result)
+}
+
+// Logout: ログアウト
+func (h *AuthHandler) Logout(c *gin.Context) {
+	if err := h.usecase.Logout(tokenOf(c)); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookie, "", -1, "/", "", c.Request.TLS != nil, true)
+	c.JSON(http.StatusOK, gin.H{"message": "Logged out"})
+}
+
+// Me: ログイン中のユーザー
+func (h *AuthHandler) Me(c *gin.Context) {
+	c.JSON(http.StatusOK, currentUser(c))
+}
+
+// ChangePassword: 自分のパスワードの変更 {"current_password": "...", "new_password": "..."}
+func (h *AuthHandler) ChangePassword(c *gin.Context) {
+	var body struct {
+		Current string `json:"current_password"`
+		New     string `json:"new_password"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	user := currentUser(c)
+	result, err := h.usecase.ChangePassword(user, body.Current, body.New)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "user", EntityID: user.ID, Action: "password", StaffID: user.StaffID}, nil, nil)
+	setSessionCookie(c, result)
+	c.JSON(http.StatusOK, result)
+}
+
+// ListUsers: アカウントの一覧
+func (h *AuthHandler) ListUsers(c *gin.Context) {
+	users, err := h.usecase.ListUsers()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, users)
+}
+
+// CreateUser: アカウントの作成 {"username": "sato", "password": "...", "role": "staff", "staff_id": 3}
+func (h *AuthHandler) CreateUser(c *gin.Context) {
+	var input usecase.UserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	user, err := h.usecase.CreateUser(currentUser(c), input)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "user", EntityID: user.ID, Action: "create", StaffID: user.StaffID}, nil, user)
+	c.JSON(http.StatusOK, user)
+}
+
+// UpdateUser: 権限・スタッフ・パスワードの変更（送った項目だけ変える）
+func (h *AuthHandler) UpdateUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var input usecase.UserInput
+	if err := c.ShouldBindJSON(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	before, after, err := h.usecase.UpdateUser(currentUser(c), uint(id), input)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "user", EntityID: after.ID, Action: "update", StaffID: after.StaffID}, before, after)
+	c.JSON(http.StatusOK, after)
+}
+
+// DeleteUser: アカウントの削除
+func (h *AuthHandler) DeleteUser(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	before, err := h.usecase.DeleteUser(currentUser(c), uint(id))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "user", EntityID: before.ID, Action: "delete", StaffID: before.StaffID}, before, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
+}
+
+// currentUser: ログイン中のユーザー（ログイン不要の経路では nil）
+func currentUser(c *gin.Context) *domain.User {
+	if v, ok := c.Get(userKey); ok {
+		return v.(*domain.User)
+	}
+	return nil
+}
+
+// allowedFor: スタッフ本人（か店長以上）でなければ 403 を返して false
+func allowedFor(c *gin.Context, staffID int) bool {
+	if user := currentUser(c); user != nil && user.CanActAs(staffID) {
+		return true
+	}
+	c.AbortWithStatusJSON(http.StatusForbidden, gin.H{"error": domain.ErrForbidden.Error()})
+	return false
+}
+
+func tokenOf(c *gin.Context) string {
+	if v := c.GetHeader("Authorization"); strings.HasPrefix(v, "Bearer ") {
+		return strings.TrimPrefix(v, "Bearer ")
+	}
+	token, _ := c.Cookie(sessionCookie)
+	return token
+}
+
+func setSessionCookie(c *gin.Context, result *domain.LoginResult) {
+	c.SetSameSite(http.SameSiteLaxMode)
+	c.SetCookie(sessionCookie, result.Token, int(time.Until(result.ExpiresAt).Seconds()), "/", "", c.Request.TLS != nil, true)
+}
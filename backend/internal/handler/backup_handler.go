@@ -0,0 +1,61 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxBackupSize: 復元で受け付けるファイルの大きさ
+const maxBackupSize = 200 << 20
+
+type BackupHandler struct {
+	usecase *usecase.BackupUsecase
+	audit   *usecase.AuditUsecase
+}
+
+func NewBackupHandler(u *usecase.BackupUsecase, audit *usecase.AuditUsecase) *BackupHandler {
+	return &BackupHandler{usecase: u, audit: audit}
+}
+
+// Export: データ全体をJSONファイルでダウンロード
+func (h *BackupHandler) Export(c *gin.Context) {
+	backup, err := h.usecase.Export()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment;filename=backup_%s.json", backup.CreatedAt.Format("20060102")))
+	c.JSON(http.StatusOK, backup)
+}
+
+// Restore: バックアップで今のデータを置き換える（?dry_run=true なら件数の確認だけ）
+// 本文にJSONをそのまま送るか、multipart の file で送る
+func (h *BackupHandler) Restore(c *gin.Context) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, maxBackupSize)
+	var body io.Reader = c.Request.Body
+	if file, _, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		body = file
+	}
+	var backup domain.Backup
+	if err := json.NewDecoder(body).Decode(&backup); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid backup file: " + err.Error()})
+		return
+	}
+
+	result, err := h.usecase.Restore(&backup, isDryRun(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !result.DryRun {
+		recordAudit(c, h.audit, domain.AuditLog{Entity: "backup", Action: "restore"}, nil, result)
+	}
+	c.JSON(http.StatusOK, result)
+}
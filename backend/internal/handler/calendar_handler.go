@@ -0,0 +1,64 @@
+package handler
+
+import (
+	"net/http"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+type CalendarHandler struct {
+	usecase *usecase.CalendarUsecase
+}
+
+func NewCalendarHandler(u *usecase.CalendarUsecase) *CalendarHandler {
+	return &CalendarHandler{usecase: u}
+}
+
+// Token: スタッフのカレンダー購読URL（未発行なら発行する）
+func (h *CalendarHandler) Token(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	token, err := h.usecase.Token(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"staff_id": id, "token": token.Token, "url": "/ical/" + token.Token + ".ics"})
+}
+
+// Rotate: 購読URLを作り直す（古いURLは使えなくなる）
+func (h *CalendarHandler) Rotate(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	token, err := h.usecase.RotateToken(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"staff_id": id, "token": token.Token, "url": "/ical/" + token.Token + ".ics"})
+}
+
+// Feed: GET /ical/:token.ics（ログイン不要。URLの合言葉で本人を判定する）
+func (h *CalendarHandler) Feed(c *gin.Context) {
+	token, ok := strings.CutSuffix(c.Param("file"), ".ics")
+	if !ok || token == "" {
+		c.String(http.StatusNotFound, "Not found")
+		return
+	}
+	feed, err := h.usecase.Feed(token)
+	if err != nil {
+		c.String(errorStatus(err), err.Error())
+		return
+	}
+	c.Header("Cache-Control", "no-cache")
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(feed))
+}
@@ -0,0 +1,66 @@
+package handler
+
+import (
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type DayOffRuleHandler struct {
+	usecase *usecase.ShiftUsecase
+}
+
+func NewDayOffRuleHandler(u *usecase.ShiftUsecase) *DayOffRuleHandler {
+	return &DayOffRuleHandler{usecase: u}
+}
+
+// Create: 休日ルールの登録
+func (h *DayOffRuleHandler) Create(c *gin.Context) {
+	var rule domain.DayOffRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	// スタッフ個別か雇用区分か、どちらかの指定が必要
+	if rule.StaffID == 0 && rule.EmploymentType == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "staff_id or employment_type is required"})
+		return
+	}
+	if rule.MinWeeklyDaysOff < 0 || rule.MinWeeklyDaysOff > 7 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "min_weekly_days_off must be between 0 and 7"})
+		return
+	}
+
+	if err := h.usecase.SaveDayOffRule(&rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// List: 休日ルールの一覧
+func (h *DayOffRuleHandler) List(c *gin.Context) {
+	rules, err := h.usecase.ListDayOffRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// Delete: 休日ルールの削除
+func (h *DayOffRuleHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.usecase.DeleteDayOffRule(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
+}
@@ -0,0 +1,35 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+)
+
+// errorStatus: Usecaseのエラーに合わせたステータスコード
+func errorStatus(err error) int {
+	switch {
+	case errors.Is(err, domain.ErrInvalidInput):
+		return http.StatusBadRequest
+	case errors.Is(err, domain.ErrUnauthorized):
+		return http.StatusUnauthorized
+	case errors.Is(err, domain.ErrForbidden):
+		return http.StatusForbidden
+	case errors.Is(err, domain.ErrNotFound):
+		return http.StatusNotFound
+	case errors.Is(err, domain.ErrRuleViolation):
+		return http.StatusUnprocessableEntity
+	case errors.Is(err, domain.ErrPeriodLocked),
+		errors.Is(err, domain.ErrInvalidPeriodStatus),
+		errors.Is(err, domain.ErrPeriodOverlap),
+		errors.Is(err, domain.ErrInvalidSwapStatus),
+		errors.Is(err, domain.ErrRequestClosed),
+		errors.Is(err, domain.ErrRequestLimit),
+		errors.Is(err, domain.ErrInvalidRequestStatus),
+		errors.Is(err, domain.ErrOpenShiftClosed),
+		errors.Is(err, domain.ErrShiftChanged):
+		return http.StatusConflict
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,122 @@
+package handler
+
+import (
+	"fmt"
+	"log"
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+type NotificationHandler struct {
+	usecase *usecase.NotificationUsecase
+}
+
+func NewNotificationHandler(u *usecase.NotificationUsecase) *NotificationHandler {
+	return &NotificationHandler{usecase: u}
+}
+
+// List: 通知の一覧 (?status=failed&staff_id=1)
+func (h *NotificationHandler) List(c *gin.Context) {
+	staffID := 0
+	if v := c.Query("staff_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff_id"})
+			return
+		}
+		staffID = id
+	}
+	list, err := h.usecase.ListNotifications(c.Query("status"), staffID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// Retry: 送信に失敗した通知をもう一度送信待ちにする
+func (h *NotificationHandler) Retry(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	n, err := h.usecase.Retry(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, n)
+}
+
+// Dispatch: 送信待ちの通知を今すぐ送る（定期実行を待たずに確認したいとき用）
+func (h *NotificationHandler) Dispatch(c *gin.Context) {
+	sent, err := h.usecase.Dispatch(time.Now())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"sent": sent})
+}
+
+// GetPreference: スタッフの通知の受け取り方
+func (h *NotificationHandler) GetPreference(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	pref, err := h.usecase.GetPreference(id)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// SavePreference: {"channels": "email,webhook", "events": "", "email": "...", "webhook_url": "..."}
+func (h *NotificationHandler) SavePreference(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var pref domain.NotificationPreference
+	if err := c.ShouldBindJSON(&pref); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	pref.StaffID = id
+
+	// Webhook の URL はサーバーから POST する先なので、決められるのは店長以上だけ（スタッフは登録済みの URL のまま）
+	if user := currentUser(c); user == nil || !user.IsManager() {
+		current, err := h.usecase.GetPreference(id)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		if pref.WebhookURL != current.WebhookURL {
+			err := fmt.Errorf("%w: Webhook の URL は店長に登録してもらってください", domain.ErrForbidden)
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	if err := h.usecase.SavePreference(&pref); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, pref)
+}
+
+// notify: 通知を積む（失敗してもリクエスト自体は成功扱いにし、ログだけ出す）
+func notify(err error) {
+	if err != nil {
+		log.Printf("通知の登録に失敗しました: %v", err)
+	}
+}
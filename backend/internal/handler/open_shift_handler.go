@@ -0,0 +1,201 @@
+package handler
+
+import (
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type OpenShiftHandler struct {
+	usecase  *usecase.OpenShiftUsecase
+	audit    *usecase.AuditUsecase
+	notifier *usecase.NotificationUsecase
+}
+
+func NewOpenShiftHandler(u *usecase.OpenShiftUsecase, audit *usecase.AuditUsecase, notifier *usecase.NotificationUsecase) *OpenShiftHandler {
+	return &OpenShiftHandler{usecase: u, audit: audit, notifier: notifier}
+}
+
+// Create: 募集シフトの追加
+// {"date": "2026-02-10", "shift_type": 2, "role": "Kitchen", "claim_mode": "priority"}
+func (h *OpenShiftHandler) Create(c *gin.Context) {
+	var open domain.OpenShift
+	if err := c.ShouldBindJSON(&open); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	if err := h.usecase.PostOpenShift(&open); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "open_shift", EntityID: open.ID, Action: "create", Date: open.Date}, nil, open)
+	c.JSON(http.StatusOK, open)
+}
+
+// List: 募集シフトの一覧 (?status=open&from=2026-02-01&to=2026-02-28)
+func (h *OpenShiftHandler) List(c *gin.Context) {
+	list, err := h.usecase.ListOpenShifts(c.Query("status"), c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	for i := range list {
+		hideOtherClaims(c, &list[i])
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// Get: 募集シフトの取得（応募込み）
+func (h *OpenShiftHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	open, err := h.usecase.GetOpenShift(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	hideOtherClaims(c, open)
+	c.JSON(http.StatusOK, open)
+}
+
+// hideOtherClaims: スタッフには自分の応募だけを見せる（店長はすべて見られる）
+func hideOtherClaims(c *gin.Context, open *domain.OpenShift) {
+	user := currentUser(c)
+	if user.IsManager() {
+		return
+	}
+	own := []domain.OpenShiftClaim{}
+	for _, claim := range open.Claims {
+		if user.CanActAs(claim.StaffID) {
+			own = append(own, claim)
+		}
+	}
+	open.Claims = own
+}
+
+// Claim: 応募 {"staff_id": 3, "shift_type": 1}（shift_type は枠が「どちらでも可」のときだけ使う）
+func (h *OpenShiftHandler) Claim(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var body struct {
+		StaffID   int `json:"staff_id"`
+		ShiftType int `json:"shift_type"`
+	}
+	c.ShouldBindJSON(&body)
+	// スタッフは自分で応募する（staff_id を省略したら自分）
+	if user := currentUser(c); body.StaffID == 0 && !user.IsManager() {
+		body.StaffID = user.StaffID
+	}
+	if body.StaffID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "staff_id is required"})
+		return
+	}
+	if !allowedFor(c, body.StaffID) {
+		return
+	}
+
+	open, violations, err := h.usecase.Claim(id, body.StaffID, body.ShiftType)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error(), "violations": violations})
+		return
+	}
+	h.recordFilled(c, open, "claim", body.StaffID)
+	c.JSON(http.StatusOK, gin.H{"open_shift": open, "violations": violations})
+}
+
+// Award: 優先度順の募集を決める {"staff_id": 3}（省略すると優先度の一番高い応募者）
+func (h *OpenShiftHandler) Award(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var body struct {
+		StaffID int `json:"staff_id"`
+	}
+	c.ShouldBindJSON(&body)
+
+	open, violations, err := h.usecase.Award(id, body.StaffID)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error(), "violations": violations})
+		return
+	}
+	h.recordFilled(c, open, "award", open.FilledBy)
+	c.JSON(http.StatusOK, gin.H{"open_shift": open, "violations": violations})
+}
+
+// Close: 募集の取り下げ
+func (h *OpenShiftHandler) Close(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.usecase.CloseOpenShift(id); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "open_shift", EntityID: uint(id), Action: "close"}, nil, nil)
+	c.JSON(http.StatusOK, gin.H{"message": "Closed"})
+}
+
+// Drop: シフトを手放して募集に出す（代わりが決まるまでは本人のシフトのまま） {"claim_mode": "first_come", "note": "..."}
+func (h *OpenShiftHandler) Drop(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var body struct {
+		ClaimMode string `json:"claim_mode"`
+		Note      string `json:"note"`
+	}
+	c.ShouldBindJSON(&body)
+
+	// スタッフが手放せるのは自分のシフトだけ
+	current, err := h.usecase.GetShift(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if !allowedFor(c, current.StaffID) {
+		return
+	}
+
+	shift, open, err := h.usecase.DropShift(id, body.ClaimMode, body.Note)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	// シフトは代わりが決まるまで残るので、ここでは募集の作成だけ残す
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "open_shift", EntityID: open.ID, Action: "create", StaffID: shift.StaffID, Date: open.Date}, nil, open)
+	c.JSON(http.StatusOK, open)
+}
+
+// recordFilled: 応募・決定の監査ログ（決まったときは作成したシフトも残す）
+func (h *OpenShiftHandler) recordFilled(c *gin.Context, open *domain.OpenShift, action string, staffID int) {
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "open_shift", EntityID: open.ID, Action: action, StaffID: staffID, Date: open.Date}, nil, open)
+	if open.Status != domain.OpenShiftFilled {
+		return
+	}
+	shift := domain.Shift{ID: open.ShiftID, StaffID: open.FilledBy, Date: open.Date, ShiftType: open.ShiftType}
+	if open.Source == domain.OpenShiftFromDrop {
+		// 手放されたシフトは、手放した人から決まった人に付け替わる
+		before := shift
+		before.StaffID = open.DroppedBy
+		recordAudit(c, h.audit, domain.AuditLog{Entity: "shift", EntityID: open.ShiftID, Action: "update", StaffID: open.FilledBy, Date: open.Date}, before, shift)
+		notify(h.notifier.ShiftChanged(&before, &shift))
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "shift", EntityID: open.ShiftID, Action: "create", StaffID: open.FilledBy, Date: open.Date}, nil, shift)
+	notify(h.notifier.ShiftChanged(nil, &shift))
+}
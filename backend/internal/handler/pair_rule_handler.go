@@ -0,0 +1,65 @@
+package handler
+
+import (
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PairRuleHandler struct {
+	usecase *usecase.ShiftUsecase
+}
+
+func NewPairRuleHandler(u *usecase.ShiftUsecase) *PairRuleHandler {
+	return &PairRuleHandler{usecase: u}
+}
+
+// Create: ペアルールの登録
+func (h *PairRuleHandler) Create(c *gin.Context) {
+	var rule domain.StaffPairRule
+	if err := c.ShouldBindJSON(&rule); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	if rule.Type != domain.PairTogether && rule.Type != domain.PairApart {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Type must be together or apart"})
+		return
+	}
+	if rule.StaffID == 0 || rule.PartnerID == 0 || rule.StaffID == rule.PartnerID {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Two different staff are required"})
+		return
+	}
+
+	if err := h.usecase.SavePairRule(&rule); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rule)
+}
+
+// List: ペアルールの一覧
+func (h *PairRuleHandler) List(c *gin.Context) {
+	rules, err := h.usecase.ListPairRules()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rules)
+}
+
+// Delete: ペアルールの削除
+func (h *PairRuleHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.usecase.DeletePairRule(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
+}
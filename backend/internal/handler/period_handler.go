@@ -0,0 +1,228 @@
+package handler
+
+import (
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PeriodHandler struct {
+	usecase  *usecase.PeriodUsecase
+	audit    *usecase.AuditUsecase
+	notifier *usecase.NotificationUsecase
+}
+
+func NewPeriodHandler(u *usecase.PeriodUsecase, audit *usecase.AuditUsecase, notifier *usecase.NotificationUsecase) *PeriodHandler {
+	return &PeriodHandler{usecase: u, audit: audit, notifier: notifier}
+}
+
+// Create: 期間の作成（下書き）
+func (h *PeriodHandler) Create(c *gin.Context) {
+	var period domain.SchedulePeriod
+	if err := c.ShouldBindJSON(&period); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	if period.StartDate == "" || period.EndDate == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date and end_date are required"})
+		return
+	}
+
+	if err := h.usecase.CreatePeriod(&period); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, period)
+}
+
+// List: 期間の一覧
+func (h *PeriodHandler) List(c *gin.Context) {
+	periods, err := h.usecase.ListPeriods()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, periods)
+}
+
+// Get: 期間の取得
+func (h *PeriodHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	period, err := h.usecase.GetPeriod(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	c.JSON(http.StatusOK, period)
+}
+
+// Delete: 期間の削除
+func (h *PeriodHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.usecase.DeletePeriod(id); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
+}
+
+// Publish: 公開
+func (h *PeriodHandler) Publish(c *gin.Context) {
+	h.transition(c, func(id int) (*domain.SchedulePeriod, error) {
+		period, err := h.usecase.Publish(id)
+		if err == nil {
+			notify(h.notifier.SchedulePublished(period))
+		}
+		return period, err
+	})
+}
+
+// Lock: 確定
+func (h *PeriodHandler) Lock(c *gin.Context) {
+	h.transition(c, h.usecase.Lock)
+}
+
+// Reopen: 再オープン（下書きに戻す）
+func (h *PeriodHandler) Reopen(c *gin.Context) {
+	h.transition(c, h.usecase.Reopen)
+}
+
+// transition: ステータス変更系の共通処理
+func (h *PeriodHandler) transition(c *gin.Context, action func(id int) (*domain.SchedulePeriod, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	period, err := action(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, period)
+}
+
+// PublishedShifts: スタッフ向けの公開済みシフト
+func (h *PeriodHandler) PublishedShifts(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	shifts, err := h.usecase.ListPublishedShifts(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, shifts)
+}
+
+// SaveVersion: 手修正をバージョンとして保存
+func (h *PeriodHandler) SaveVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var req struct {
+		Note string `json:"note"`
+	}
+	// note は任意なのでボディが空でもよい
+	_ = c.ShouldBindJSON(&req)
+
+	version, err := h.usecase.SaveVersion(id, req.Note)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, version)
+}
+
+// ListVersions: バージョン一覧
+func (h *PeriodHandler) ListVersions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	versions, err := h.usecase.ListVersions(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, versions)
+}
+
+// GetVersion: バージョンの中身
+func (h *PeriodHandler) GetVersion(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return
+	}
+	version, err := h.usecase.GetVersion(id, number)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, version)
+}
+
+// DiffVersions: 2つのバージョンの差分 (?from=1&to=2)
+func (h *PeriodHandler) DiffVersions(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	from, err1 := strconv.Atoi(c.Query("from"))
+	to, err2 := strconv.Atoi(c.Query("to"))
+	if err1 != nil || err2 != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+		return
+	}
+	diff, err := h.usecase.DiffVersions(id, from, to)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, diff)
+}
+
+// Rollback: 過去のバージョンに戻す
+func (h *PeriodHandler) Rollback(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	number, err := strconv.Atoi(c.Param("version"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid version"})
+		return
+	}
+	version, err := h.usecase.Rollback(id, number)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "period", EntityID: uint(id), Action: "rollback"},
+		nil, gin.H{"period_id": id, "restored_version": number, "new_version": version.Version})
+	c.JSON(http.StatusOK, version)
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ReplacementHandler struct {
+	usecase  *usecase.ReplacementUsecase
+	audit    *usecase.AuditUsecase
+	notifier *usecase.NotificationUsecase
+}
+
+func NewReplacementHandler(u *usecase.ReplacementUsecase, audit *usecase.AuditUsecase, notifier *usecase.NotificationUsecase) *ReplacementHandler {
+	return &ReplacementHandler{usecase: u, audit: audit, notifier: notifier}
+}
+
+// List: シフトの代わりに入れる人の候補（入れる人が先、スコアの小さい順）
+func (h *ReplacementHandler) List(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	result, err := h.usecase.FindReplacements(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// Reassign: 欠勤したシフトを代わりの人に付け替える {"staff_id": 3, "reason": "体調不良"}
+func (h *ReplacementHandler) Reassign(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	var body struct {
+		StaffID int    `json:"staff_id"`
+		Reason  string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&body); err != nil || body.StaffID == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "staff_id is required"})
+		return
+	}
+
+	result, err := h.usecase.Reassign(id, body.StaffID, body.Reason, actorOf(c))
+	if err != nil {
+		var violations []domain.Violation
+		if result != nil {
+			violations = result.Violations
+		}
+		c.JSON(errorStatus(err), gin.H{"error": err.Error(), "violations": violations})
+		return
+	}
+
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "call_out", EntityID: result.CallOut.ID, Action: "create", StaffID: result.CallOut.AbsentStaffID, Date: result.CallOut.Date}, nil, result.CallOut)
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "shift", EntityID: result.After.ID, Action: "call_out", StaffID: result.After.StaffID, Date: result.After.Date}, result.Before, result.After)
+	notify(h.notifier.ShiftChanged(&result.Before, &result.After))
+	c.JSON(http.StatusOK, gin.H{"call_out": result.CallOut, "shift": result.After, "violations": result.Violations})
+}
+
+// ListCallOuts: 欠勤の記録 (?from=2026-02-01&to=2026-02-28)
+func (h *ReplacementHandler) ListCallOuts(c *gin.Context) {
+	list, err := h.usecase.ListCallOuts(c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
@@ -10,11 +10,13 @@ import (
 )
 
 type RequestHandler struct {
-	usecase *usecase.ShiftUsecase
+	usecase  *usecase.ShiftUsecase
+	audit    *usecase.AuditUsecase
+	notifier *usecase.NotificationUsecase
 }
 
-func NewRequestHandler(u *usecase.ShiftUsecase) *RequestHandler {
-	return &RequestHandler{usecase: u}
+func NewRequestHandler(u *usecase.ShiftUsecase, audit *usecase.AuditUsecase, notifier *usecase.NotificationUsecase) *RequestHandler {
+	return &RequestHandler{usecase: u, audit: audit, notifier: notifier}
 }
 
 // Create: 希望休の登録
@@ -24,12 +26,20 @@ func (h *RequestHandler) Create(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
 		return
 	}
+	// スタッフは自分の分だけ（staff_id を省略したら自分）
+	if user := currentUser(c); req.StaffID == 0 && !user.IsManager() {
+		req.StaffID = user.StaffID
+	}
+	if !allowedFor(c, req.StaffID) {
+		return
+	}
 
 	// ★修正: AddRequest -> CreateRequest
 	if err := h.usecase.CreateRequest(&req); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "request", EntityID: req.ID, Action: "create", StaffID: req.StaffID, Date: req.Date}, nil, req)
 
 	c.JSON(http.StatusOK, req)
 }
@@ -42,9 +52,54 @@ func (h *RequestHandler) List(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+
+	// ?status=pending で承認待ちだけに絞る。スタッフには自分の分だけ見せる
+	user := currentUser(c)
+	if status := c.Query("status"); status != "" || !user.IsManager() {
+		filtered := []domain.ShiftRequest{}
+		for _, r := range requests {
+			if (status == "" || r.Status == status) && user.CanActAs(r.StaffID) {
+				filtered = append(filtered, r)
+			}
+		}
+		requests = filtered
+	}
 	c.JSON(http.StatusOK, requests)
 }
 
+// Approve: 休み希望の承認 ({"comment": "..."})
+func (h *RequestHandler) Approve(c *gin.Context) {
+	h.review(c, true)
+}
+
+// Reject: 休み希望の却下 ({"comment": "..."})
+func (h *RequestHandler) Reject(c *gin.Context) {
+	h.review(c, false)
+}
+
+func (h *RequestHandler) review(c *gin.Context, approve bool) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	before, err := h.usecase.GetRequest(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	after, err := h.usecase.ReviewRequest(id, approve, actorOf(c), commentOf(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "request", EntityID: after.ID, Action: after.Status, StaffID: after.StaffID, Date: after.Date}, before, after)
+	notify(h.notifier.RequestReviewed(after))
+
+	c.JSON(http.StatusOK, after)
+}
+
 // Delete: 希望休の削除
 func (h *RequestHandler) Delete(c *gin.Context) {
 	idStr := c.Param("id")
@@ -55,11 +110,77 @@ func (h *RequestHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	before, err := h.usecase.GetRequest(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+	// スタッフが取り消せるのは自分の承認待ちの希望だけ（承認・却下の後は店長に頼む）
+	if !allowedFor(c, before.StaffID) {
+		return
+	}
+	if !currentUser(c).IsManager() && before.Status != domain.RequestPending {
+		c.JSON(http.StatusConflict, gin.H{"error": domain.ErrInvalidRequestStatus.Error()})
+		return
+	}
+
 	// ★修正: DeleteRequest (int型を渡す)
 	if err := h.usecase.DeleteRequest(id); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "request", EntityID: before.ID, Action: "delete", StaffID: before.StaffID, Date: before.Date}, before, nil)
 
 	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
-}
\ No newline at end of file
+}
+
+// PaidLeave: 全スタッフの有給休暇の残日数・取得義務の状況 (?as_of=2026-04-01)
+func (h *RequestHandler) PaidLeave(c *gin.Context) {
+	balances, err := h.usecase.PaidLeaveBalances(c.Query("as_of"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, balances)
+}
+
+// StaffPaidLeave: スタッフ1人の有給休暇の状況
+func (h *RequestHandler) StaffPaidLeave(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	balance, err := h.usecase.PaidLeaveBalance(id, c.Query("as_of"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, balance)
+}
+
+// Import: 休み希望の一括取り込み (POST /api/request/import?dry_run=true&status=approved)
+// 列は staff_id / employee_code / name のどれか、date、type（NG・PAID）、note
+func (h *RequestHandler) Import(c *gin.Context) {
+	table, err := readImportFile(c)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.usecase.ImportRequests(table, c.Query("status"), actorOf(c), isDryRun(c))
+	if err != nil {
+		if result != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error(), "result": result})
+			return
+		}
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if result.Applied {
+		for _, row := range result.Rows {
+			req := row.Request
+			recordAudit(c, h.audit, domain.AuditLog{Entity: "request", EntityID: req.ID, Action: "import", StaffID: req.StaffID, Date: req.Date}, nil, req)
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type RoleConstraintHandler struct {
+	usecase *usecase.ShiftUsecase
+}
+
+func NewRoleConstraintHandler(u *usecase.ShiftUsecase) *RoleConstraintHandler {
+	return &RoleConstraintHandler{usecase: u}
+}
+
+// Create: 役割ごとの人数ルールの登録（例: Leader 1人以上）
+func (h *RoleConstraintHandler) Create(c *gin.Context) {
+	var rc domain.RoleConstraint
+	if err := c.ShouldBindJSON(&rc); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	if rc.Role == "" || rc.Count <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "role and count are required"})
+		return
+	}
+	rc.ID = 0
+
+	if err := h.usecase.SaveRoleConstraint(&rc); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, rc)
+}
+
+// List: 役割ごとの人数ルールの一覧
+func (h *RoleConstraintHandler) List(c *gin.Context) {
+	list, err := h.usecase.ListRoleConstraints()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, list)
+}
+
+// Delete: 役割ごとの人数ルールの削除
+func (h *RoleConstraintHandler) Delete(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if err := h.usecase.DeleteRoleConstraint(id); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
+}
@@ -1,21 +1,28 @@
 package handler
 
 import (
+	"bytes"
 	"encoding/csv"
+	"fmt"
+	"log"
 	"net/http"
 	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/export"
 	"smart-shift-scheduler/internal/usecase"
 	"strconv"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
 type ShiftHandler struct {
-	usecase *usecase.ShiftUsecase
+	usecase  *usecase.ShiftUsecase
+	audit    *usecase.AuditUsecase
+	notifier *usecase.NotificationUsecase
 }
 
-func NewShiftHandler(u *usecase.ShiftUsecase) *ShiftHandler {
-	return &ShiftHandler{usecase: u}
+func NewShiftHandler(u *usecase.ShiftUsecase, audit *usecase.AuditUsecase, notifier *usecase.NotificationUsecase) *ShiftHandler {
+	return &ShiftHandler{usecase: u, audit: audit, notifier: notifier}
 }
 
 // Generate: シフト生成
@@ -26,21 +33,82 @@ func (h *ShiftHandler) Generate(c *gin.Context) {
 		return
 	}
 
+	// period_id があれば期間の開始日・日数を使う
+	if err := h.usecase.ResolvePeriod(&input); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period_id"})
+		return
+	}
 	startDate := input.StartDate
 	if startDate == "" {
-		// input.StartDateが無い場合のフォールバック（JSON構造によってはこっち）
-		// 今回はJSONにstart_dateが含まれている前提
-		startDate = "2026-02-01" 
+		c.JSON(http.StatusBadRequest, gin.H{"error": "start_date or period_id is required"})
+		return
 	}
 
-	if err := h.usecase.GenerateAndSave(input, startDate); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	// 修正モード: repair_from 以降だけを組み直し、変わった割り当てを返す
+	if input.Mode == domain.GenerateModeRepair {
+		if input.RepairFrom == "" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "repair_from is required"})
+			return
+		}
+		changes, err := h.usecase.Repair(input, startDate)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		h.recordChanges(c, "repair", changes)
+		h.notifyChanges(changes)
+		c.JSON(http.StatusOK, gin.H{"message": "シフトを修正しました", "changes": changes})
+		return
+	}
+
+	changes, err := h.usecase.Generate(input, startDate)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
+	h.recordChanges(c, "generate", changes)
 
+	// 埋まらなかった枠があれば一緒に返す
+	if input.AllowOpenShifts {
+		openShifts, err := h.usecase.OpenShiftsFrom(startDate, input.Days)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.JSON(http.StatusOK, gin.H{"message": "シフトを作成・保存しました", "open_shifts": openShifts})
+		return
+	}
 	c.JSON(http.StatusOK, gin.H{"message": "シフトを作成・保存しました"})
 }
 
+// recordChanges: 生成・修正で変わった割り当てごとに監査ログを残す（0 = 休みは「無し」として記録する）
+func (h *ShiftHandler) recordChanges(c *gin.Context, action string, changes []domain.ShiftChange) {
+	for _, change := range changes {
+		var before, after interface{}
+		if change.Before != 0 {
+			before = domain.Shift{StaffID: change.StaffID, Date: change.Date, ShiftType: change.Before}
+		}
+		if change.After != 0 {
+			after = domain.Shift{StaffID: change.StaffID, Date: change.Date, ShiftType: change.After}
+		}
+		recordAudit(c, h.audit, domain.AuditLog{Entity: "shift", Action: action, StaffID: change.StaffID, Date: change.Date, Source: domain.AuditSourceGeneration}, before, after)
+	}
+}
+
+// notifyChanges: 変わった割り当てごとに本人へ通知する（公開済み・確定済みの期間だけ。下書きは ShiftChanged 側で除く）
+func (h *ShiftHandler) notifyChanges(changes []domain.ShiftChange) {
+	for _, change := range changes {
+		var before, after *domain.Shift
+		if change.Before != 0 {
+			before = &domain.Shift{StaffID: change.StaffID, Date: change.Date, ShiftType: change.Before}
+		}
+		if change.After != 0 {
+			after = &domain.Shift{StaffID: change.StaffID, Date: change.Date, ShiftType: change.After}
+		}
+		notify(h.notifier.ShiftChanged(before, after))
+	}
+}
+
 // List: シフト一覧
 func (h *ShiftHandler) List(c *gin.Context) {
 	// ★修正: GetAllShifts -> ListShifts
@@ -52,6 +120,56 @@ func (h *ShiftHandler) List(c *gin.Context) {
 	c.JSON(http.StatusOK, shifts)
 }
 
+// Evaluate: 保存済みシフトのルール違反とKPI (?from=2026-02-01&to=2026-02-28)
+func (h *ShiftHandler) Evaluate(c *gin.Context) {
+	from := c.Query("from")
+	to := c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+		return
+	}
+
+	eval, err := h.usecase.Evaluate(from, to)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, eval)
+}
+
+// Coverage: 日付×シフト区分の必要人数・配置人数（ヒートマップ用）
+// ?from=2026-02-01&to=2026-02-28&format=csv でCSV出力
+func (h *ShiftHandler) Coverage(c *gin.Context) {
+	from := c.Query("from")
+	to := c.Query("to")
+	if from == "" || to == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from and to are required"})
+		return
+	}
+
+	days, err := h.usecase.Coverage(from, to)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if c.Query("format") == "csv" {
+		c.Header("Content-Type", "text/csv")
+		c.Header("Content-Disposition", "attachment;filename=coverage.csv")
+		c.Writer.Write([]byte{0xEF, 0xBB, 0xBF})
+		writer := csv.NewWriter(c.Writer)
+		// WriteAll は最後に Flush して writer.Error() を返す
+		if err := writer.WriteAll(usecase.CoverageCSVRows(days)); err != nil {
+			// ヘッダーは送信済みなので、ログに残して途中で打ち切る
+			log.Printf("カバレッジの CSV の出力に失敗しました: %v", err)
+			c.Abort()
+		}
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{"from": from, "to": to, "templates": domain.ShiftTemplates, "days": days})
+}
+
 // Update: シフト修正（移動など）
 func (h *ShiftHandler) Update(c *gin.Context) {
 	idStr := c.Param("id")
@@ -83,17 +201,70 @@ func (h *ShiftHandler) Update(c *gin.Context) {
 		// ShiftTypeなどは必要ならフロントから送るが、
 		// 現在のドラッグ&ドロップ実装だと日付変更がメイン
 	}
+	if st, ok := req["shift_type"].(float64); ok {
+		shift.ShiftType = int(st)
+	}
+	// override: true ならルール違反を承知の上で保存する（意図的な例外）
+	override, _ := req["override"].(bool)
 
 	// ★修正: MoveShift -> UpdateShift
 	// UpdateShiftは全フィールド更新の可能性があるので、
 	// 本来は「既存データを取得して書き換える」のが安全だが、
 	// GORMのUpdatesを使っていれば指定フィールドのみ更新される
-	if err := h.usecase.UpdateShift(shift); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	before, err := h.usecase.GetShift(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
 		return
 	}
 
-	c.JSON(http.StatusOK, gin.H{"message": "Updated"})
+	violations, err := h.usecase.UpdateShift(shift, override)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error(), "violations": violations})
+		return
+	}
+	if after, err := h.usecase.GetShift(id); err == nil {
+		action := "update"
+		if override && len(violations) > 0 {
+			action = "update_override"
+		}
+		recordAudit(c, h.audit, domain.AuditLog{Entity: "shift", EntityID: after.ID, Action: action, StaffID: after.StaffID, Date: after.Date}, before, after)
+		notify(h.notifier.ShiftChanged(before, after))
+	}
+
+	// 警告（または override した違反）があれば一緒に返す
+	c.JSON(http.StatusOK, gin.H{"message": "Updated", "violations": violations})
+}
+
+// Lock: シフトのロック／ロック解除（ロック中は再生成しても変わらない）
+func (h *ShiftHandler) Lock(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+
+	var req struct {
+		Locked bool `json:"locked"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+
+	before, err := h.usecase.GetShift(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
+	if err := h.usecase.LockShift(id, req.Locked); err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	after := *before
+	after.Locked = req.Locked
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "shift", EntityID: before.ID, Action: "lock", StaffID: before.StaffID, Date: before.Date}, before, after)
+	c.JSON(http.StatusOK, gin.H{"message": "Updated", "locked": req.Locked})
 }
 
 // Delete: シフト削除
@@ -106,11 +277,19 @@ func (h *ShiftHandler) Delete(c *gin.Context) {
 		return
 	}
 
+	before, err := h.usecase.GetShift(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
 	// ★修正: DeleteShift (intを渡す)
 	if err := h.usecase.DeleteShift(id); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "shift", EntityID: before.ID, Action: "delete", StaffID: before.StaffID, Date: before.Date}, before, nil)
+	notify(h.notifier.ShiftChanged(before, nil))
 
 	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
 }
@@ -122,10 +301,28 @@ func (h *ShiftHandler) SaveRequirement(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
 		return
 	}
+	// 同じ日の設定があれば上書きになるので、変更前として残す
+	var before interface{}
+	existing, err := h.usecase.FindRequirementByDate(req.Date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	action := "create"
+	if existing != nil {
+		before = existing
+		action = "update"
+	}
+
 	if err := h.usecase.SaveRequirement(&req); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	entityID := req.ID
+	if existing != nil {
+		entityID = existing.ID
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "requirement", EntityID: entityID, Action: action, Date: req.Date}, before, req)
 	c.JSON(http.StatusOK, req)
 }
 
@@ -146,70 +343,154 @@ func (h *ShiftHandler) DeleteRequirement(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
 		return
 	}
+	before, err := h.usecase.GetRequirement(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
 	if err := h.usecase.DeleteRequirement(id); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "requirement", EntityID: before.ID, Action: "delete", Date: before.Date}, before, nil)
 	c.JSON(http.StatusOK, gin.H{"message": "Deleted"})
 }
 
 
 
+// ImportRequirements: 必要人数の一括取り込み (POST /api/requirement/import?dry_run=true&overwrite=true)
+// 列は date、morning_need、evening_need（見出しは 日付・早番・遅番 でもよい）
+func (h *ShiftHandler) ImportRequirements(c *gin.Context) {
+	table, err := readImportFile(c)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	overwrite := c.Query("overwrite") == "true" || c.Query("overwrite") == "1"
+	result, err := h.usecase.ImportRequirements(table, overwrite, isDryRun(c))
+	if err != nil {
+		if result != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error(), "result": result})
+			return
+		}
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if result.Applied {
+		for _, row := range result.Rows {
+			var before interface{} // 新規なら変更前は無し
+			if row.Before != nil {
+				before = row.Before
+			}
+			recordAudit(c, h.audit, domain.AuditLog{Entity: "requirement", EntityID: row.Requirement.ID, Action: "import", Date: row.Requirement.Date}, before, row.Requirement)
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
 // Export: CSV出力
 func (h *ShiftHandler) Export(c *gin.Context) {
-	// 1. 全シフト取得
-	shifts, err := h.usecase.ListShifts()
-	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	if c.Query("format") == "xlsx" {
+		h.exportXLSX(c)
+		return
+	}
+	if c.Query("format") == "pdf" {
+		h.exportPDF(c)
 		return
 	}
 
-	// 2. スタッフ名を取得
-	// ★修正: StaffRepoを直接触らず、専用のメソッドを使うようにしました
-	staffList, err := h.usecase.ListStaff()
+	// 1. 絞り込み条件と列・文字コードを確かめる（書き出し始めるとエラーを返せないため先に）
+	query := usecase.ShiftExportQuery{From: c.Query("from"), To: c.Query("to"), Role: c.Query("role")}
+	for _, v := range queryList(c, "staff_id") {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff_id"})
+			return
+		}
+		query.StaffIDs = append(query.StaffIDs, id)
+	}
+	columns, err := export.CSVColumns(queryList(c, "columns"))
 	if err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
-
-	// IDと名前の対照表を作る
-	staffMap := make(map[int]string)
-	for _, s := range staffList {
-		staffMap[int(s.ID)] = s.Name
+	writer, err := export.NewShiftCSVWriter(c.Writer, c.Query("encoding"), columns)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	shifts, err := h.usecase.PrepareShiftExport(query)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
 	}
 
-	// 3. CSV作成
-	c.Header("Content-Type", "text/csv")
+	// 2. 1件ずつ書き出す（全件をメモリに載せない）
+	c.Header("Content-Type", writer.ContentType())
 	c.Header("Content-Disposition", "attachment;filename=shift.csv")
-	c.Writer.Write([]byte{0xEF, 0xBB, 0xBF})
-	// Shift-JISにするなら変換が必要ですが、一旦UTF-8で出力します
-	writer := csv.NewWriter(c.Writer)
-
-	
-	
-	// ヘッダー書き込み
-	writer.Write([]string{"日付", "スタッフ名", "シフト種別", "時間"})
-
-	for _, s := range shifts {
-		name := staffMap[s.StaffID]
-		typeStr := ""
-		timeStr := ""
-		
-		switch s.ShiftType {
-		case 1:
-			typeStr = "早番"
-			timeStr = "09:00-18:00"
-		case 2:
-			typeStr = "遅番"
-			timeStr = "18:00-23:00"
+	c.Status(http.StatusOK)
+	err = writer.WriteHeader()
+	if err == nil {
+		err = shifts.Each(func(s domain.Shift) error {
+			return writer.Write(s, shifts.Staff[s.StaffID])
+		})
+	}
+	if err == nil {
+		err = writer.Close()
+	}
+	if err != nil {
+		// ヘッダーは送信済みなので、ログに残して途中で打ち切る
+		log.Printf("CSV の出力に失敗しました: %v", err)
+		c.Abort()
+	}
+}
+
+// queryList: カンマ区切りと同じ名前の繰り返しのどちらでも受け付ける (?staff_id=1,2&staff_id=3)
+func queryList(c *gin.Context, key string) []string {
+	var list []string
+	for _, v := range c.QueryArray(key) {
+		for _, item := range strings.Split(v, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				list = append(list, item)
+			}
 		}
+	}
+	return list
+}
 
-		writer.Write([]string{
-			s.Date,
-			name,
-			typeStr,
-			timeStr,
-		})
+// exportXLSX: スタッフ×日付のシフト表を Excel で出力 (?format=xlsx&from=2026-02-01&to=2026-02-28)
+func (h *ShiftHandler) exportXLSX(c *gin.Context) {
+	grid, err := h.usecase.ScheduleGrid(c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	var buf bytes.Buffer
+	if err := export.WriteScheduleXLSX(&buf, grid); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
 	}
-	writer.Flush()
-}
\ No newline at end of file
+	c.Header("Content-Disposition", fmt.Sprintf("attachment;filename=shift_%s_%s.xlsx", grid.From, grid.To))
+	c.Data(http.StatusOK, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", buf.Bytes())
+}
+
+// exportPDF: 印刷用のシフト表を PDF で出力 (?format=pdf&from=2026-02-01&to=2026-02-28&paper=a3)
+func (h *ShiftHandler) exportPDF(c *gin.Context) {
+	paper, ok := export.PaperSizeOf(c.Query("paper"))
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "paper must be a4 or a3"})
+		return
+	}
+	grid, err := h.usecase.ScheduleGrid(c.Query("from"), c.Query("to"))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	var buf bytes.Buffer
+	if err := export.WriteSchedulePDF(&buf, grid, paper); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment;filename=shift_%s_%s.pdf", grid.From, grid.To))
+	c.Data(http.StatusOK, "application/pdf", buf.Bytes())
+}
@@ -4,17 +4,21 @@ import (
 	"fmt" // ★fmtを忘れずに！
 	"net/http"
 	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/importer"
 	"smart-shift-scheduler/internal/usecase"
+	"strings"
+	"time"
 
 	"github.com/gin-gonic/gin"
 )
 
 type StaffHandler struct {
 	usecase *usecase.StaffUsecase
+	audit   *usecase.AuditUsecase
 }
 
-func NewStaffHandler(u *usecase.StaffUsecase) *StaffHandler {
-	return &StaffHandler{usecase: u}
+func NewStaffHandler(u *usecase.StaffUsecase, audit *usecase.AuditUsecase) *StaffHandler {
+	return &StaffHandler{usecase: u, audit: audit}
 }
 
 // Create: スタッフ登録
@@ -24,6 +28,16 @@ func (h *StaffHandler) Create(c *gin.Context) {
 		IsLeader   bool   `json:"is_leader"`
 		HourlyWage int    `json:"hourly_wage"`
 		Roles      string `json:"roles"` // ★受け皿を追加
+
+		EmployeeCode string `json:"employee_code"`
+
+		EmploymentType         string  `json:"employment_type"`
+		MaxRequestDaysPerMonth int     `json:"max_request_days_per_month"`
+		HireDate               string  `json:"hire_date"`
+		WeeklyScheduledDays    int     `json:"weekly_scheduled_days"`
+		WeeklyScheduledHours   float64 `json:"weekly_scheduled_hours"`
+		MaxWeeklyHours         float64 `json:"max_weekly_hours"`
+		AnnualIncomeCap        int     `json:"annual_income_cap"`
 	}
 
 	var req CreateStaffRequest
@@ -37,12 +51,39 @@ func (h *StaffHandler) Create(c *gin.Context) {
 		IsLeader:   req.IsLeader,
 		HourlyWage: req.HourlyWage,
 		Roles:      req.Roles, // ★ここも追加
+
+		EmployeeCode:           strings.TrimSpace(req.EmployeeCode),
+
+		EmploymentType:         req.EmploymentType,
+		MaxRequestDaysPerMonth: req.MaxRequestDaysPerMonth,
+		HireDate:               req.HireDate,
+		WeeklyScheduledDays:    req.WeeklyScheduledDays,
+		WeeklyScheduledHours:   req.WeeklyScheduledHours,
+		MaxWeeklyHours:         req.MaxWeeklyHours,
+		AnnualIncomeCap:        req.AnnualIncomeCap,
+	}
+
+	if staff.HireDate != "" {
+		if _, err := time.Parse("2006-01-02", staff.HireDate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "hire_date must be YYYY-MM-DD"})
+			return
+		}
+	}
+	if staff.WeeklyScheduledDays < 0 || staff.WeeklyScheduledDays > 7 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "weekly_scheduled_days must be 0-7"})
+		return
+	}
+
+	if staff.MaxWeeklyHours < 0 || staff.AnnualIncomeCap < 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "max_weekly_hours and annual_income_cap must not be negative"})
+		return
 	}
 
 	if err := h.usecase.CreateStaff(staff); err != nil {
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
 		return
 	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "staff", EntityID: staff.ID, Action: "create", StaffID: int(staff.ID)}, nil, staff)
 
 	c.JSON(http.StatusOK, staff)
 }
@@ -53,6 +94,14 @@ func (h *StaffHandler) List(c *gin.Context) {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// 給与まわりは本人と店長以上にだけ見せる
+	user := currentUser(c)
+	for i := range staffList {
+		if !user.CanActAs(int(staffList[i].ID)) {
+			staffList[i].HourlyWage = 0
+			staffList[i].AnnualIncomeCap = 0
+		}
+	}
 	c.JSON(http.StatusOK, staffList)
 }
 
@@ -62,9 +111,65 @@ func (h *StaffHandler) Delete(c *gin.Context) {
 	var id uint
 	fmt.Sscanf(idStr, "%d", &id)
 
+	before, err := h.usecase.GetStaff(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Not found"})
+		return
+	}
+
 	if err := h.usecase.DeleteStaff(id); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "staff", EntityID: id, Action: "delete", StaffID: int(id)}, before, nil)
 	c.JSON(http.StatusOK, gin.H{"message": "削除しました"})
-}
\ No newline at end of file
+}
+
+// Import: スタッフ名簿の一括取り込み (POST /api/staff/import?dry_run=true&mode=upsert)
+// ファイルは multipart の file か、リクエスト本文そのまま（CSV・.xlsx）
+func (h *StaffHandler) Import(c *gin.Context) {
+	table, err := readImportFile(c)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	result, err := h.usecase.ImportStaff(table, c.Query("mode"), isDryRun(c))
+	if err != nil {
+		if result != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error(), "result": result})
+			return
+		}
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+
+	if result.Applied {
+		for _, row := range result.Rows {
+			var before interface{} // 新規登録なら変更前は無し
+			if row.Before != nil {
+				before = row.Before
+			}
+			recordAudit(c, h.audit, domain.AuditLog{Entity: "staff", EntityID: row.Staff.ID, Action: "import", StaffID: int(row.Staff.ID)}, before, row.Staff)
+		}
+	}
+	c.JSON(http.StatusOK, result)
+}
+
+// isDryRun: ?dry_run=true なら確認だけで保存しない
+func isDryRun(c *gin.Context) bool {
+	return c.Query("dry_run") == "true" || c.Query("dry_run") == "1"
+}
+
+// readImportFile: 取り込みファイルを読む（multipart の file、無ければ本文）
+func readImportFile(c *gin.Context) (*domain.ImportTable, error) {
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, importer.MaxFileSize+1<<20)
+	if file, header, err := c.Request.FormFile("file"); err == nil {
+		defer file.Close()
+		return importer.ReadTable(file, header.Filename)
+	}
+	name := ""
+	if strings.Contains(c.ContentType(), "spreadsheetml") {
+		name = "upload.xlsx"
+	}
+	return importer.ReadTable(c.Request.Body, name)
+}
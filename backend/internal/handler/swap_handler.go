@@ -0,0 +1,173 @@
+package handler
+
+import (
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"smart-shift-scheduler/internal/usecase"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SwapHandler struct {
+	usecase  *usecase.SwapUsecase
+	audit    *usecase.AuditUsecase
+	notifier *usecase.NotificationUsecase
+}
+
+func NewSwapHandler(u *usecase.SwapUsecase, audit *usecase.AuditUsecase, notifier *usecase.NotificationUsecase) *SwapHandler {
+	return &SwapHandler{usecase: u, audit: audit, notifier: notifier}
+}
+
+// Create: 交換の申請
+// {"type": "give", "requester_id": 1, "target_id": 2, "shift_id": 10, "note": "..."}
+// {"type": "trade", ..., "target_shift_id": 11}
+func (h *SwapHandler) Create(c *gin.Context) {
+	var swap domain.ShiftSwap
+	if err := c.ShouldBindJSON(&swap); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid data"})
+		return
+	}
+	if !allowedFor(c, swap.RequesterID) {
+		return
+	}
+
+	violations, err := h.usecase.ProposeSwap(&swap, actorOf(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error(), "violations": violations})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "swap", EntityID: swap.ID, Action: "propose", StaffID: swap.RequesterID}, nil, swap)
+	c.JSON(http.StatusOK, gin.H{"swap": swap, "violations": violations})
+}
+
+// List: 交換申請の一覧 (?status=accepted&staff_id=1)
+func (h *SwapHandler) List(c *gin.Context) {
+	staffID := 0
+	if v := c.Query("staff_id"); v != "" {
+		id, err := strconv.Atoi(v)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid staff_id"})
+			return
+		}
+		staffID = id
+	}
+	// スタッフには自分が申請者か相手の申請だけ見せる
+	if user := currentUser(c); !user.IsManager() {
+		staffID = user.StaffID
+	}
+
+	swaps, err := h.usecase.ListSwaps(c.Query("status"), staffID)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, swaps)
+}
+
+// Get: 交換申請の取得（状態の履歴込み）
+func (h *SwapHandler) Get(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	swap, err := h.usecase.GetSwap(id)
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	if user := currentUser(c); !user.CanActAs(swap.RequesterID) && !user.CanActAs(swap.TargetID) {
+		c.JSON(http.StatusForbidden, gin.H{"error": domain.ErrForbidden.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, swap)
+}
+
+// Accept: 相手が承諾
+func (h *SwapHandler) Accept(c *gin.Context) {
+	h.transition(c, "accept", swapTarget, h.usecase.AcceptSwap)
+}
+
+// Decline: 相手が辞退
+func (h *SwapHandler) Decline(c *gin.Context) {
+	h.transition(c, "decline", swapTarget, h.usecase.DeclineSwap)
+}
+
+// Cancel: 申請者が取り下げ
+func (h *SwapHandler) Cancel(c *gin.Context) {
+	h.transition(c, "cancel", swapRequester, h.usecase.CancelSwap)
+}
+
+// Reject: 店長が却下
+func (h *SwapHandler) Reject(c *gin.Context) {
+	h.transition(c, "reject", nil, h.usecase.RejectSwap)
+}
+
+// 状態を変えられる本人（店長以上は誰の分でも）
+func swapTarget(swap *domain.ShiftSwap) int    { return swap.TargetID }
+func swapRequester(swap *domain.ShiftSwap) int { return swap.RequesterID }
+
+// Approve: 店長が承認（シフトに反映）
+func (h *SwapHandler) Approve(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	comment := commentOf(c)
+
+	result, err := h.usecase.ApproveSwap(id, actorOf(c), comment)
+	if err != nil {
+		var violations []domain.Violation
+		if result != nil {
+			violations = result.Violations
+		}
+		c.JSON(errorStatus(err), gin.H{"error": err.Error(), "violations": violations})
+		return
+	}
+
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "swap", EntityID: result.Swap.ID, Action: "approve", StaffID: result.Swap.RequesterID}, nil, result.Swap)
+	for i := range result.Before {
+		before, after := result.Before[i], result.After[i]
+		recordAudit(c, h.audit, domain.AuditLog{Entity: "shift", EntityID: after.ID, Action: "swap", StaffID: after.StaffID, Date: after.Date}, before, after)
+	}
+	notify(h.notifier.SwapApproved(result))
+	c.JSON(http.StatusOK, gin.H{"swap": result.Swap, "violations": result.Violations})
+}
+
+// transition: 状態だけを変える操作の共通処理（party が nil なら店長以上だけ、経路で絞る）
+func (h *SwapHandler) transition(c *gin.Context, action string, party func(*domain.ShiftSwap) int, fn func(id int, actor string, comment string) (*domain.ShiftSwap, error)) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID"})
+		return
+	}
+	if party != nil {
+		current, err := h.usecase.GetSwap(id)
+		if err != nil {
+			c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+			return
+		}
+		if !allowedFor(c, party(current)) {
+			return
+		}
+	}
+
+	swap, err := fn(id, actorOf(c), commentOf(c))
+	if err != nil {
+		c.JSON(errorStatus(err), gin.H{"error": err.Error()})
+		return
+	}
+	recordAudit(c, h.audit, domain.AuditLog{Entity: "swap", EntityID: swap.ID, Action: action, StaffID: swap.RequesterID}, nil, swap)
+	c.JSON(http.StatusOK, swap)
+}
+
+// commentOf: {"comment": "..."}（ボディが無くてもよい）
+func commentOf(c *gin.Context) string {
+	var body struct {
+		Comment string `json:"comment"`
+	}
+	c.ShouldBindJSON(&body)
+	return body.Comment
+}
@@ -0,0 +1,104 @@
+// Package importer: 取り込み用のファイル（CSV・Excel）を行×列の文字列にする
+package importer
+
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"path/filepath"
+	"smart-shift-scheduler/internal/domain"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/japanese"
+)
+
+// MaxFileSize: 取り込めるファイルの大きさの上限
+const MaxFileSize = 10 << 20
+
+// ReadTable: CSV か .xlsx（最初のシートのみ）を読む
+// 形式はファイル名の拡張子、無ければ中身（zip かどうか）で判定する
+// CSV は UTF-8（BOM あり・なし）と Shift_JIS（CP932）を受け付ける
+func ReadTable(r io.Reader, filename string) (*domain.ImportTable, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxFileSize+1))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) > MaxFileSize {
+		return nil, fmt.Errorf("%w: ファイルが %dMB を超えています", domain.ErrInvalidInput, MaxFileSize>>20)
+	}
+
+	var records [][]string
+	ext := strings.ToLower(filepath.Ext(filename))
+	if ext == ".xlsx" || (ext == "" && bytes.HasPrefix(data, []byte("PK\x03\x04"))) {
+		records, err = readXLSX(data)
+	} else {
+		records, err = readCSV(data)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	table := &domain.ImportTable{}
+	for i, record := range records {
+		if isBlank(record) {
+			continue
+		}
+		if table.Header == nil {
+			table.Header = trimAll(record)
+			continue
+		}
+		table.Rows = append(table.Rows, domain.ImportRow{Line: i + 1, Values: trimAll(record)})
+	}
+	if table.Header == nil {
+		return nil, fmt.Errorf("%w: ファイルが空です", domain.ErrInvalidInput)
+	}
+	return table, nil
+}
+
+func readCSV(data []byte) ([][]string, error) {
+	data = bytes.TrimPrefix(data, []byte{0xEF, 0xBB, 0xBF})
+	if !utf8.Valid(data) {
+		decoded, err := japanese.ShiftJIS.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, fmt.Errorf("%w: CSV は UTF-8 か Shift_JIS で保存してください", domain.ErrInvalidInput)
+		}
+		data = decoded
+	}
+	reader := csv.NewReader(bytes.NewReader(data))
+	reader.FieldsPerRecord = -1 // 列数が揃っていない行も読む（足りない列は空として扱う）
+	var records [][]string
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			return records, nil
+		}
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", domain.ErrInvalidInput, err)
+		}
+		// セル内の改行があっても、行番号はファイル上の行（レコードの開始行）に合わせる
+		line, _ := reader.FieldPos(0)
+		for len(records) < line-1 {
+			records = append(records, nil)
+		}
+		records = append(records, record)
+	}
+}
+
+func isBlank(record []string) bool {
+	for _, v := range record {
+		if strings.TrimSpace(v) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+func trimAll(record []string) []string {
+	out := make([]string, len(record))
+	for i, v := range record {
+		out[i] = strings.TrimSpace(v)
+	}
+	return out
+}
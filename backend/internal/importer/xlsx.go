@@ -0,0 +1,194 @@
+package importer
+
+import (
+	"archive/zip"
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"path"
+	"smart-shift-scheduler/internal/domain"
+	"strconv"
+	"strings"
+)
+
+// readXLSX: 最初のシートを行×列の文字列にする（数式は保存された計算結果を使う）
+func readXLSX(data []byte) ([][]string, error) {
+	zr, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("%w: .xlsx ファイルとして読み込めません", domain.ErrInvalidInput)
+	}
+	files := make(map[string]*zip.File)
+	for _, f := range zr.File {
+		files[f.Name] = f
+	}
+
+	sheetPath, err := firstSheetPath(files)
+	if err != nil {
+		return nil, err
+	}
+	var shared []string
+	if f, ok := files["xl/sharedStrings.xml"]; ok {
+		if shared, err = readSharedStrings(f); err != nil {
+			return nil, err
+		}
+	}
+	f, ok := files[sheetPath]
+	if !ok {
+		return nil, fmt.Errorf("%w: シート %s が見つかりません", domain.ErrInvalidInput, sheetPath)
+	}
+	return readSheet(f, shared)
+}
+
+// firstSheetPath: workbook.xml の最初のシートのファイル名
+func firstSheetPath(files map[string]*zip.File) (string, error) {
+	var workbook struct {
+		Sheets []struct {
+			ID string `xml:"http://schemas.openxmlformats.org/officeDocument/2006/relationships id,attr"`
+		} `xml:"sheets>sheet"`
+	}
+	var rels struct {
+		Relationships []struct {
+			ID     string `xml:"Id,attr"`
+			Target string `xml:"Target,attr"`
+		} `xml:"Relationship"`
+	}
+	if err := decodeXML(files["xl/workbook.xml"], &workbook); err != nil {
+		return "", err
+	}
+	if err := decodeXML(files["xl/_rels/workbook.xml.rels"], &rels); err != nil {
+		return "", err
+	}
+	if len(workbook.Sheets) == 0 {
+		return "", fmt.Errorf("%w: ブックにシートがありません", domain.ErrInvalidInput)
+	}
+	for _, rel := range rels.Relationships {
+		if rel.ID != workbook.Sheets[0].ID {
+			continue
+		}
+		if strings.HasPrefix(rel.Target, "/") {
+			return strings.TrimPrefix(rel.Target, "/"), nil
+		}
+		return path.Join("xl", rel.Target), nil
+	}
+	return "", fmt.Errorf("%w: 最初のシートが見つかりません", domain.ErrInvalidInput)
+}
+
+// xlsxText: 文字列（書式付きの文字列は r ごとの t をつなげる）
+type xlsxText struct {
+	T    string `xml:"t"`
+	Runs []struct {
+		T string `xml:"t"`
+	} `xml:"r"`
+}
+
+func (t xlsxText) String() string {
+	if len(t.Runs) == 0 {
+		return t.T
+	}
+	var b strings.Builder
+	for _, r := range t.Runs {
+		b.WriteString(r.T)
+	}
+	return b.String()
+}
+
+func readSharedStrings(f *zip.File) ([]string, error) {
+	var sst struct {
+		Items []xlsxText `xml:"si"`
+	}
+	if err := decodeXML(f, &sst); err != nil {
+		return nil, err
+	}
+	shared := make([]string, len(sst.Items))
+	for i, item := range sst.Items {
+		shared[i] = item.String()
+	}
+	return shared, nil
+}
+
+func readSheet(f *zip.File, shared []string) ([][]string, error) {
+	var sheet struct {
+		Rows []struct {
+			R     int `xml:"r,attr"`
+			Cells []struct {
+				R      string   `xml:"r,attr"`
+				T      string   `xml:"t,attr"`
+				V      string   `xml:"v"`
+				Inline xlsxText `xml:"is"`
+			} `xml:"c"`
+		} `xml:"sheetData>row"`
+	}
+	if err := decodeXML(f, &sheet); err != nil {
+		return nil, err
+	}
+
+	var records [][]string
+	for i, row := range sheet.Rows {
+		line := row.R
+		if line == 0 {
+			line = i + 1
+		}
+		for len(records) < line {
+			records = append(records, nil) // 空行も数えて行番号をファイルと合わせる
+		}
+		var record []string
+		for j, cell := range row.Cells {
+			col := columnIndex(cell.R)
+			if col < 0 {
+				col = j
+			}
+			for len(record) <= col {
+				record = append(record, "")
+			}
+			switch cell.T {
+			case "s":
+				n, err := strconv.Atoi(cell.V)
+				if err != nil || n < 0 || n >= len(shared) {
+					return nil, fmt.Errorf("%w: %s の文字列が壊れています", domain.ErrInvalidInput, cell.R)
+				}
+				record[col] = shared[n]
+			case "inlineStr":
+				record[col] = cell.Inline.String()
+			case "b":
+				record[col] = map[string]string{"1": "TRUE", "0": "FALSE"}[cell.V]
+			default:
+				record[col] = cell.V
+			}
+		}
+		records[line-1] = record
+	}
+	return records, nil
+}
+
+// columnIndex: "C12" -> 2（0始まり）
+func columnIndex(ref string) int {
+	col := 0
+	n := 0
+	for _, r := range ref {
+		if r < 'A' || r > 'Z' {
+			break
+		}
+		col = col*26 + int(r-'A'+1)
+		n++
+	}
+	if n == 0 {
+		return -1
+	}
+	return col - 1
+}
+
+func decodeXML(f *zip.File, v any) error {
+	if f == nil {
+		return fmt.Errorf("%w: .xlsx ファイルとして読み込めません", domain.ErrInvalidInput)
+	}
+	rc, err := f.Open()
+	if err != nil {
+		return err
+	}
+	defer rc.Close()
+	if err := xml.NewDecoder(io.LimitReader(rc, 64<<20)).Decode(v); err != nil {
+		return fmt.Errorf("%w: .xlsx ファイルとして読み込めません", domain.ErrInvalidInput)
+	}
+	return nil
+}
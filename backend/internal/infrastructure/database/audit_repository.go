@@ -0,0 +1,54 @@
+package database
+
+import (
+	"smart-shift-scheduler/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type AuditRepository struct {
+	db *gorm.DB
+}
+
+func NewAuditRepository(db *gorm.DB) *AuditRepository {
+	return &AuditRepository{db: db}
+}
+
+// Save: 追記のみ（更新・削除のメソッドは用意しない）
+func (r *AuditRepository) Save(log *domain.AuditLog) error {
+	return r.db.Create(log).Error
+}
+
+// Find: 条件に合うログを新しい順に取得
+func (r *AuditRepository) Find(filter domain.AuditFilter) ([]domain.AuditLog, error) {
+	q := r.db.Model(&domain.AuditLog{})
+	if filter.Entity != "" {
+		q = q.Where("entity = ?", filter.Entity)
+	}
+	if filter.EntityID != 0 {
+		q = q.Where("entity_id = ?", filter.EntityID)
+	}
+	if filter.StaffID != 0 {
+		q = q.Where("staff_id = ?", filter.StaffID)
+	}
+	if filter.Date != "" {
+		q = q.Where("date = ?", filter.Date)
+	}
+	if filter.From != "" {
+		if from, err := time.ParseInLocation("2006-01-02", filter.From, time.Local); err == nil {
+			q = q.Where("created_at >= ?", from)
+		}
+	}
+	if filter.To != "" {
+		if to, err := time.ParseInLocation("2006-01-02", filter.To, time.Local); err == nil {
+			q = q.Where("created_at < ?", to.AddDate(0, 0, 1))
+		}
+	}
+
+	var logs []domain.AuditLog
+	if err := q.Order("created_at DESC, id DESC").Find(&logs).Error; err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
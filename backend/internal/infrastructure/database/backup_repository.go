@@ -0,0 +1,295 @@
+package database
+
+import (
+	"smart-shift-scheduler/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type BackupRepository struct {
+	db *gorm.DB
+}
+
+func NewBackupRepository(db *gorm.DB) *BackupRepository {
+	return &BackupRepository{db: db}
+}
+
+// Dump: バックアップに含めるテーブルを全件読む（ID順）
+func (r *BackupRepository) Dump() (*domain.BackupData, error) {
+	var data domain.BackupData
+	byID := func(db *gorm.DB) *gorm.DB { return db.Order("id") }
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		for _, q := range []struct {
+			query *gorm.DB
+			dest  interface{}
+		}{
+			{tx, &data.Staff},
+			{tx, &data.Periods},
+			{tx, &data.Shifts},
+			{tx, &data.Requests},
+			{tx, &data.Requirements},
+			{tx, &data.PairRules},
+			{tx, &data.DayOffRules},
+			{tx, &data.RoleConstraints},
+			{tx, &data.PublishedShifts},
+			{tx.Preload("Shifts", byID), &data.Versions},
+			{tx.Preload("History", byID), &data.Swaps},
+			{tx.Preload("Claims", byID), &data.OpenShifts},
+			{tx, &data.CallOuts},
+			{tx, &data.NotificationPreferences},
+		} {
+			if err := q.query.Order("id").Find(q.dest).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &data, nil
+}
+
+// Restore: 今のデータを消してバックアップの中身を入れる（1トランザクション）
+// ID は振り直し、スタッフ・期間・シフトへの参照を新しい ID に読み替える
+// カレンダーの合言葉と通知の送信待ちは消す（前のスタッフの ID を指しているため）
+// ログインアカウントは残し、紐づくスタッフを社員番号（無ければ名前）で付け替える。見つからなければ紐づけを外して返す
+// 監査ログも残し、スタッフへの参照を同じように付け替える（見つからなければ0）
+// シフトなど他の行の ID は読み替えられないので、復元したこと自体を監査ログに残して境目にする（handler 側）
+func (r *BackupRepository) Restore(data *domain.BackupData) ([]string, error) {
+	var unlinked []string
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		var current []domain.Staff
+		if err := tx.Find(&current).Error; err != nil {
+			return err
+		}
+		var users []domain.User
+		if err := tx.Where("staff_id <> 0").Order("id").Find(&users).Error; err != nil {
+			return err
+		}
+
+		for _, model := range []interface{}{
+			&domain.CallOut{}, &domain.OpenShiftClaim{}, &domain.OpenShift{}, &domain.SwapEvent{}, &domain.ShiftSwap{},
+			&domain.VersionShift{}, &domain.ScheduleVersion{}, &domain.PublishedShift{}, &domain.RoleConstraint{},
+			&domain.DayOffRule{}, &domain.StaffPairRule{}, &domain.DailyRequirement{}, &domain.ShiftRequest{},
+			&domain.Shift{}, &domain.SchedulePeriod{}, &domain.NotificationPreference{}, &domain.Notification{},
+			&domain.CalendarToken{}, &domain.Staff{},
+		} {
+			if err := tx.Where("1 = 1").Delete(model).Error; err != nil {
+				return err
+			}
+		}
+
+		staffIDs := make(map[int]int)
+		for i := range data.Staff {
+			old := int(data.Staff[i].ID)
+			data.Staff[i].ID = 0
+			if err := tx.Create(&data.Staff[i]).Error; err != nil {
+				return err
+			}
+			staffIDs[old] = int(data.Staff[i].ID)
+		}
+		staff := func(id int) int { return staffIDs[id] } // 0 は 0 のまま
+
+		// 復元前のスタッフ ID → 復元後のスタッフ ID（見つからなければ0）
+		matched := make(map[int]int)
+		for _, s := range current {
+			matched[int(s.ID)] = matchStaff(s, data.Staff)
+		}
+		if err := remapAuditStaff(tx, matched); err != nil {
+			return err
+		}
+
+		if len(users) > 0 {
+			for i := range users {
+				users[i].StaffID = matched[users[i].StaffID]
+				if users[i].StaffID == 0 {
+					unlinked = append(unlinked, users[i].Username)
+				}
+				if err := tx.Model(&users[i]).Update("staff_id", users[i].StaffID).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		periodIDs := make(map[uint]uint)
+		for i := range data.Periods {
+			old := data.Periods[i].ID
+			data.Periods[i].ID = 0
+			if err := tx.Create(&data.Periods[i]).Error; err != nil {
+				return err
+			}
+			periodIDs[old] = data.Periods[i].ID
+		}
+
+		oldShiftIDs := make([]uint, len(data.Shifts))
+		for i := range data.Shifts {
+			oldShiftIDs[i] = data.Shifts[i].ID
+			data.Shifts[i].ID = 0
+			data.Shifts[i].StaffID = staff(data.Shifts[i].StaffID)
+		}
+		if err := createAll(tx, data.Shifts); err != nil {
+			return err
+		}
+		shiftIDs := make(map[uint]uint)
+		for i, old := range oldShiftIDs {
+			shiftIDs[old] = data.Shifts[i].ID
+		}
+		shift := func(id uint) uint { return shiftIDs[id] } // 消えたシフトは 0（指していない）にする
+
+		for i := range data.Requests {
+			data.Requests[i].ID = 0
+			data.Requests[i].StaffID = staff(data.Requests[i].StaffID)
+		}
+		for i := range data.Requirements {
+			data.Requirements[i].ID = 0
+		}
+		for i := range data.PairRules {
+			data.PairRules[i].ID = 0
+			data.PairRules[i].StaffID = staff(data.PairRules[i].StaffID)
+			data.PairRules[i].PartnerID = staff(data.PairRules[i].PartnerID)
+		}
+		for i := range data.DayOffRules {
+			data.DayOffRules[i].ID = 0
+			data.DayOffRules[i].StaffID = staff(data.DayOffRules[i].StaffID)
+		}
+		for i := range data.RoleConstraints {
+			data.RoleConstraints[i].ID = 0
+		}
+		for i := range data.PublishedShifts {
+			data.PublishedShifts[i].ID = 0
+			data.PublishedShifts[i].PeriodID = periodIDs[data.PublishedShifts[i].PeriodID]
+			data.PublishedShifts[i].StaffID = staff(data.PublishedShifts[i].StaffID)
+		}
+		for i := range data.Versions {
+			v := &data.Versions[i]
+			v.ID = 0
+			v.PeriodID = periodIDs[v.PeriodID]
+			for j := range v.Shifts {
+				v.Shifts[j].ID, v.Shifts[j].VersionID = 0, 0
+				v.Shifts[j].StaffID = staff(v.Shifts[j].StaffID)
+			}
+		}
+		for i := range data.Swaps {
+			s := &data.Swaps[i]
+			s.ID = 0
+			s.RequesterID, s.TargetID = staff(s.RequesterID), staff(s.TargetID)
+			s.ShiftID, s.TargetShiftID = shift(s.ShiftID), shift(s.TargetShiftID)
+			for j := range s.History {
+				s.History[j].ID, s.History[j].SwapID = 0, 0
+			}
+		}
+		for i := range data.OpenShifts {
+			o := &data.OpenShifts[i]
+			o.ID = 0
+			o.DroppedBy, o.FilledBy = staff(o.DroppedBy), staff(o.FilledBy)
+			o.ShiftID = shift(o.ShiftID)
+			for j := range o.Claims {
+				o.Claims[j].ID, o.Claims[j].OpenShiftID = 0, 0
+				o.Claims[j].StaffID = staff(o.Claims[j].StaffID)
+			}
+		}
+		for i := range data.CallOuts {
+			c := &data.CallOuts[i]
+			c.ID = 0
+			c.AbsentStaffID, c.ReplacementID = staff(c.AbsentStaffID), staff(c.ReplacementID)
+			c.ShiftID = shift(c.ShiftID)
+		}
+		for i := range data.NotificationPreferences {
+			data.NotificationPreferences[i].ID = 0
+			data.NotificationPreferences[i].StaffID = staff(data.NotificationPreferences[i].StaffID)
+		}
+
+		// 入れ子（バージョンのシフト・交換の履歴・募集への応募）は親と一緒に保存される
+		for _, create := range []func() error{
+			func() error { return createAll(tx, data.Requests) },
+			func() error { return createAll(tx, data.Requirements) },
+			func() error { return createAll(tx, data.PairRules) },
+			func() error { return createAll(tx, data.DayOffRules) },
+			func() error { return createAll(tx, data.RoleConstraints) },
+			func() error { return createAll(tx, data.PublishedShifts) },
+			func() error { return createAll(tx, data.Versions) },
+			func() error { return createAll(tx, data.Swaps) },
+			func() error { return createAll(tx, data.OpenShifts) },
+			func() error { return createAll(tx, data.CallOuts) },
+			func() error { return createAll(tx, data.NotificationPreferences) },
+		} {
+			if err := create(); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return unlinked, nil
+}
+
+// remapAuditStaff: 監査ログのスタッフへの参照（staff_id と、スタッフの監査ログの entity_id）を付け替える
+// 削除済みのスタッフなど、復元後に見つからない参照は0にする（新しい ID と重ならないように）
+// 付け替え先が別のスタッフの元の ID と重なることがあるので、先に対象の行をすべて読んでから更新する
+func remapAuditStaff(tx *gorm.DB, matched map[int]int) error {
+	type ref struct {
+		ID    uint
+		Value int
+	}
+	for _, q := range []struct {
+		column string
+		where  string
+	}{
+		{"staff_id", "staff_id <> 0"},
+		{"entity_id", "entity = 'staff' AND entity_id <> 0"},
+	} {
+		var refs []ref
+		if err := tx.Model(&domain.AuditLog{}).Select("id, " + q.column + " AS value").Where(q.where).Scan(&refs).Error; err != nil {
+			return err
+		}
+		byValue := make(map[int][]uint) // 付け替え後の値 → 監査ログの ID
+		for _, r := range refs {
+			if next := matched[r.Value]; next != r.Value {
+				byValue[next] = append(byValue[next], r.ID)
+			}
+		}
+		for value, ids := range byValue {
+			for start := 0; start < len(ids); start += 500 {
+				end := min(start+500, len(ids))
+				if err := tx.Model(&domain.AuditLog{}).Where("id IN ?", ids[start:end]).Update(q.column, value).Error; err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// matchStaff: 復元したスタッフから同じ人を探す（社員番号、無ければ名前が1人だけ一致する人。見つからなければ0）
+func matchStaff(prev domain.Staff, restored []domain.Staff) int {
+	if prev.ID == 0 {
+		return 0
+	}
+	found := 0
+	for _, s := range restored {
+		if prev.EmployeeCode != "" {
+			if s.EmployeeCode == prev.EmployeeCode {
+				return int(s.ID)
+			}
+			continue
+		}
+		if s.Name == prev.Name {
+			if found != 0 {
+				return 0 // 同じ名前が2人以上
+			}
+			found = int(s.ID)
+		}
+	}
+	return found
+}
+
+// createAll: まとめて保存（空なら何もしない。GORM は空のスライスを渡すとエラーにする）
+func createAll[T any](tx *gorm.DB, rows []T) error {
+	if len(rows) == 0 {
+		return nil
+	}
+	return tx.CreateInBatches(rows, 500).Error
+}
@@ -0,0 +1,60 @@
+package database
+
+import (
+	"errors"
+	"smart-shift-scheduler/internal/domain"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type CalendarRepository struct {
+	db *gorm.DB
+}
+
+func NewCalendarRepository(db *gorm.DB) *CalendarRepository {
+	return &CalendarRepository{db: db}
+}
+
+// FindToken: スタッフの合言葉（未発行なら nil）
+func (r *CalendarRepository) FindToken(staffID int) (*domain.CalendarToken, error) {
+	var token domain.CalendarToken
+	err := r.db.Where("staff_id = ?", staffID).First(&token).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &token, nil
+}
+
+// FindByToken: 合言葉からスタッフを引く
+func (r *CalendarRepository) FindByToken(token string) (*domain.CalendarToken, error) {
+	var t domain.CalendarToken
+	err := r.db.Where("token = ?", token).First(&t).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// SaveToken: スタッフごとに1件（あれば作り直す）
+func (r *CalendarRepository) SaveToken(token *domain.CalendarToken) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "staff_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"token", "created_at"}),
+	}).Create(token).Error
+}
+
+// FindPublishedShifts: スタッフの公開済みシフト（取消も含め、全期間）
+func (r *CalendarRepository) FindPublishedShifts(staffID int) ([]domain.PublishedShift, error) {
+	var shifts []domain.PublishedShift
+	if err := r.db.Where("staff_id = ?", staffID).Order("date, id").Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
@@ -0,0 +1,52 @@
+package database
+
+import (
+	"smart-shift-scheduler/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type CallOutRepository struct {
+	db *gorm.DB
+}
+
+func NewCallOutRepository(db *gorm.DB) *CallOutRepository {
+	return &CallOutRepository{db: db}
+}
+
+// Find: 欠勤の記録の一覧（from, to は空なら絞り込まない）
+func (r *CallOutRepository) Find(from string, to string) ([]domain.CallOut, error) {
+	query := r.db.Order("date, id")
+	if from != "" {
+		query = query.Where("date >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("date <= ?", to)
+	}
+	var list []domain.CallOut
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Reassign: シフトを代わりの人に付け替えて、欠勤を記録する
+// シフトが読み込んだ後に変わっていたら ErrShiftChanged
+func (r *CallOutRepository) Reassign(shift *domain.Shift, callOut *domain.CallOut) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.Shift{}).
+			Where("id = ? AND staff_id = ? AND date = ? AND shift_type = ?", shift.ID, callOut.AbsentStaffID, shift.Date, shift.ShiftType).
+			Update("staff_id", callOut.ReplacementID)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrShiftChanged
+		}
+		if err := tx.Create(callOut).Error; err != nil {
+			return err
+		}
+		shift.StaffID = callOut.ReplacementID
+		return nil
+	})
+}
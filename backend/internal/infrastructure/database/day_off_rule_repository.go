@@ -0,0 +1,31 @@
+package database
+
+import (
+	"smart-shift-scheduler/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type DayOffRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewDayOffRuleRepository(db *gorm.DB) *DayOffRuleRepository {
+	return &DayOffRuleRepository{db: db}
+}
+
+func (r *DayOffRuleRepository) Save(rule *domain.DayOffRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *DayOffRuleRepository) FindAll() ([]domain.DayOffRule, error) {
+	var rules []domain.DayOffRule
+	if err := r.db.Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *DayOffRuleRepository) Delete(id int) error {
+	return r.db.Delete(&domain.DayOffRule{}, id).Error
+}
@@ -0,0 +1,126 @@
+package database
+
+import (
+	"errors"
+	"smart-shift-scheduler/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type NotificationRepository struct {
+	db *gorm.DB
+}
+
+func NewNotificationRepository(db *gorm.DB) *NotificationRepository {
+	return &NotificationRepository{db: db}
+}
+
+// Enqueue: 通知をまとめてアウトボックスに入れる
+func (r *NotificationRepository) Enqueue(list []domain.Notification) error {
+	if len(list) == 0 {
+		return nil
+	}
+	return r.db.Create(&list).Error
+}
+
+// ClaimDue: now の時点で送る時刻になっている通知を古い順に limit 件取り出し、送信中にする
+// 行ロックを取れたものだけを取るので、同時に動く送信処理と同じ通知を取り合わない
+// 送信中のまま lease を過ぎたもの（送っている途中でプロセスが落ちたなど）はもう一度取り出す
+func (r *NotificationRepository) ClaimDue(now time.Time, lease time.Duration, limit int) ([]domain.Notification, error) {
+	var list []domain.Notification
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE", Options: "SKIP LOCKED"}).
+			Where("status IN ? AND next_attempt_at <= ?", []string{domain.NotificationPending, domain.NotificationSending}, now).
+			Order("next_attempt_at, id").Limit(limit).Find(&list).Error; err != nil {
+			return err
+		}
+		if len(list) == 0 {
+			return nil
+		}
+		ids := make([]uint, len(list))
+		for i := range list {
+			ids[i] = list[i].ID
+			list[i].Status = domain.NotificationSending
+			list[i].NextAttemptAt = now.Add(lease)
+		}
+		return tx.Model(&domain.Notification{}).Where("id IN ?", ids).Updates(map[string]interface{}{
+			"status":          domain.NotificationSending,
+			"next_attempt_at": now.Add(lease),
+		}).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// Find: 通知の一覧（status, staffID は空・0なら絞り込まない、新しい順）
+func (r *NotificationRepository) Find(status string, staffID int) ([]domain.Notification, error) {
+	query := r.db.Order("id desc").Limit(500)
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if staffID != 0 {
+		query = query.Where("staff_id = ?", staffID)
+	}
+	var list []domain.Notification
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *NotificationRepository) FindByID(id int) (*domain.Notification, error) {
+	var n domain.Notification
+	err := r.db.First(&n, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &n, nil
+}
+
+// Update: 送信結果（状態・試行回数・次の送信時刻・エラー）を保存
+func (r *NotificationRepository) Update(n *domain.Notification) error {
+	return r.db.Model(&domain.Notification{}).Where("id = ?", n.ID).Updates(map[string]interface{}{
+		"status":          n.Status,
+		"attempts":        n.Attempts,
+		"next_attempt_at": n.NextAttemptAt,
+		"last_error":      n.LastError,
+		"sent_at":         n.SentAt,
+	}).Error
+}
+
+// FindPreferences: 全スタッフの受け取り方
+func (r *NotificationRepository) FindPreferences() ([]domain.NotificationPreference, error) {
+	var list []domain.NotificationPreference
+	if err := r.db.Order("staff_id").Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// FindPreference: スタッフの受け取り方（未設定なら nil）
+func (r *NotificationRepository) FindPreference(staffID int) (*domain.NotificationPreference, error) {
+	var pref domain.NotificationPreference
+	err := r.db.Where("staff_id = ?", staffID).First(&pref).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &pref, nil
+}
+
+// SavePreference: スタッフごとに1件（あれば上書き）
+func (r *NotificationRepository) SavePreference(pref *domain.NotificationPreference) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "staff_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"channels", "events", "email", "webhook_url", "updated_at"}),
+	}).Create(pref).Error
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type OpenShiftRepository struct {
+	db *gorm.DB
+}
+
+func NewOpenShiftRepository(db *gorm.DB) *OpenShiftRepository {
+	return &OpenShiftRepository{db: db}
+}
+
+func (r *OpenShiftRepository) Save(open *domain.OpenShift) error {
+	return r.db.Create(open).Error
+}
+
+// Find: 募集シフトの一覧（status, from, to は空なら絞り込まない）
+func (r *OpenShiftRepository) Find(status string, from string, to string) ([]domain.OpenShift, error) {
+	query := r.db.Order("date, shift_type, id")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if from != "" {
+		query = query.Where("date >= ?", from)
+	}
+	if to != "" {
+		query = query.Where("date <= ?", to)
+	}
+	var list []domain.OpenShift
+	if err := query.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+// FindByID: 応募込みで取得
+func (r *OpenShiftRepository) FindByID(id int) (*domain.OpenShift, error) {
+	var open domain.OpenShift
+	err := r.db.Preload("Claims", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).First(&open, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &open, nil
+}
+
+// replaceGeneratedOpenShifts: 期間内の「生成で埋まらなかった」募集中の枠を入れ替える（決まったものは残す）
+// 生成結果のシフトと同じトランザクションで呼ぶ（ShiftRepository.SaveGenerated）
+func replaceGeneratedOpenShifts(tx *gorm.DB, from string, to string, list []domain.OpenShift) error {
+	var ids []uint
+	if err := tx.Model(&domain.OpenShift{}).
+		Where("date >= ? AND date <= ? AND source = ? AND status = ?", from, to, domain.OpenShiftFromGeneration, domain.OpenShiftOpen).
+		Pluck("id", &ids).Error; err != nil {
+		return err
+	}
+	if len(ids) > 0 {
+		if err := tx.Where("open_shift_id IN ?", ids).Delete(&domain.OpenShiftClaim{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Delete(&domain.OpenShift{}, ids).Error; err != nil {
+			return err
+		}
+	}
+	if len(list) == 0 {
+		return nil
+	}
+	return tx.Create(&list).Error
+}
+
+// Drop: 同じ枠を募集シフトにする（シフトは決まるまで消さない。同じシフトを二重に募集しない）
+func (r *OpenShiftRepository) Drop(shift *domain.Shift, open *domain.OpenShift) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var count int64
+		if err := tx.Model(&domain.Shift{}).
+			Where("id = ? AND staff_id = ? AND date = ? AND shift_type = ?", shift.ID, shift.StaffID, shift.Date, shift.ShiftType).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count == 0 {
+			return domain.ErrNotFound
+		}
+		if err := tx.Model(&domain.OpenShift{}).
+			Where("shift_id = ? AND status = ?", shift.ID, domain.OpenShiftOpen).
+			Count(&count).Error; err != nil {
+			return err
+		}
+		if count > 0 {
+			return fmt.Errorf("%w: このシフトはすでに募集中です", domain.ErrInvalidInput)
+		}
+		return tx.Create(open).Error
+	})
+}
+
+// AddClaim: 応募を追加
+func (r *OpenShiftRepository) AddClaim(claim *domain.OpenShiftClaim) error {
+	return r.db.Create(claim).Error
+}
+
+// Fill: シフトを作成して募集を締め切る（募集中のときだけ）
+// shift.ID が0でなければ手放されたシフトなので、作成せずに担当者を付け替える
+// claimID が0でなければその応募を当選、ほかの応募を落選にする
+func (r *OpenShiftRepository) Fill(open *domain.OpenShift, shift *domain.Shift, claimID uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if shift.ID != 0 {
+			result := tx.Model(&domain.Shift{}).
+				Where("id = ? AND staff_id = ?", shift.ID, open.DroppedBy).
+				Updates(map[string]interface{}{"staff_id": shift.StaffID, "shift_type": shift.ShiftType})
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return domain.ErrShiftChanged
+			}
+		} else if err := tx.Create(shift).Error; err != nil {
+			return err
+		}
+		now := time.Now()
+		result := tx.Model(&domain.OpenShift{}).
+			Where("id = ? AND status = ?", open.ID, domain.OpenShiftOpen).
+			Updates(map[string]interface{}{
+				"status":     domain.OpenShiftFilled,
+				"shift_type": shift.ShiftType, // 「どちらでも可」の枠は決まった区分にする
+				"filled_by":  shift.StaffID,
+				"shift_id":   shift.ID,
+				"filled_at":  now,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		// 同時に別の人で決まっていた
+		if result.RowsAffected == 0 {
+			return domain.ErrOpenShiftClosed
+		}
+		if err := tx.Model(&domain.OpenShiftClaim{}).
+			Where("open_shift_id = ? AND status = ?", open.ID, domain.ClaimPending).
+			Update("status", domain.ClaimLost).Error; err != nil {
+			return err
+		}
+		if claimID != 0 {
+			if err := tx.Model(&domain.OpenShiftClaim{}).Where("id = ?", claimID).Update("status", domain.ClaimWon).Error; err != nil {
+				return err
+			}
+		}
+		open.Status = domain.OpenShiftFilled
+		open.ShiftType = shift.ShiftType
+		open.FilledBy = shift.StaffID
+		open.ShiftID = shift.ID
+		open.FilledAt = &now
+		return nil
+	})
+}
+
+// Close: 募集を取り下げる（募集中のときだけ）
+func (r *OpenShiftRepository) Close(id int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&domain.OpenShift{}).
+			Where("id = ? AND status = ?", id, domain.OpenShiftOpen).
+			Update("status", domain.OpenShiftClosed)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return domain.ErrOpenShiftClosed
+		}
+		return tx.Model(&domain.OpenShiftClaim{}).
+			Where("open_shift_id = ? AND status = ?", id, domain.ClaimPending).
+			Update("status", domain.ClaimLost).Error
+	})
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"smart-shift-scheduler/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type PairRuleRepository struct {
+	db *gorm.DB
+}
+
+func NewPairRuleRepository(db *gorm.DB) *PairRuleRepository {
+	return &PairRuleRepository{db: db}
+}
+
+func (r *PairRuleRepository) Save(rule *domain.StaffPairRule) error {
+	return r.db.Create(rule).Error
+}
+
+func (r *PairRuleRepository) FindAll() ([]domain.StaffPairRule, error) {
+	var rules []domain.StaffPairRule
+	if err := r.db.Find(&rules).Error; err != nil {
+		return nil, err
+	}
+	return rules, nil
+}
+
+func (r *PairRuleRepository) Delete(id int) error {
+	return r.db.Delete(&domain.StaffPairRule{}, id).Error
+}
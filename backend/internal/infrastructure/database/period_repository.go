@@ -0,0 +1,163 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type PeriodRepository struct {
+	db *gorm.DB
+}
+
+func NewPeriodRepository(db *gorm.DB) *PeriodRepository {
+	return &PeriodRepository{db: db}
+}
+
+func (r *PeriodRepository) Save(period *domain.SchedulePeriod) error {
+	return r.db.Create(period).Error
+}
+
+func (r *PeriodRepository) FindAll() ([]domain.SchedulePeriod, error) {
+	var periods []domain.SchedulePeriod
+	if err := r.db.Order("start_date").Find(&periods).Error; err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+func (r *PeriodRepository) FindByID(id int) (*domain.SchedulePeriod, error) {
+	var period domain.SchedulePeriod
+	err := r.db.First(&period, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// FindCovering: その日付を含む期間を取得（無ければ nil）
+func (r *PeriodRepository) FindCovering(date string) (*domain.SchedulePeriod, error) {
+	var period domain.SchedulePeriod
+	err := r.db.Where("start_date <= ? AND end_date >= ?", date, date).First(&period).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &period, nil
+}
+
+// FindOverlapping: 指定範囲と日付が重なる期間を取得
+func (r *PeriodRepository) FindOverlapping(startDate string, endDate string) ([]domain.SchedulePeriod, error) {
+	var periods []domain.SchedulePeriod
+	if err := r.db.Where("start_date <= ? AND end_date >= ?", endDate, startDate).Find(&periods).Error; err != nil {
+		return nil, err
+	}
+	return periods, nil
+}
+
+// UpdateStatus: ステータスだけを更新
+func (r *PeriodRepository) UpdateStatus(id uint, status string) error {
+	return r.db.Model(&domain.SchedulePeriod{}).Where("id = ?", id).Update("status", status).Error
+}
+
+// Publish: 期間内のシフトを公開用にコピーし、ステータスを公開済みにする（まとめて1トランザクション）
+// 前回の公開分とスタッフ×日付で突き合わせ、変わったものだけ Sequence を上げる。無くなったものは取消にする
+func (r *PeriodRepository) Publish(period *domain.SchedulePeriod, shifts []domain.Shift) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var existing []domain.PublishedShift
+		if err := tx.Where("period_id = ?", period.ID).Order("id").Find(&existing).Error; err != nil {
+			return err
+		}
+		key := func(staffID int, date string) string { return fmt.Sprintf("%d/%s", staffID, date) }
+
+		// 1人1日1件（二重登録の2件目以降は公開しない）
+		next := make(map[string]domain.Shift)
+		var order []string
+		for _, s := range shifts {
+			k := key(s.StaffID, s.Date)
+			if _, ok := next[k]; ok || s.ShiftType == 0 {
+				continue
+			}
+			next[k] = s
+			order = append(order, k)
+		}
+
+		now := time.Now()
+		prev := make(map[string]domain.PublishedShift)
+		for _, p := range existing {
+			k := key(p.StaffID, p.Date)
+			if _, ok := prev[k]; ok {
+				// 差分で反映する前に作られた重複は消す
+				if err := tx.Delete(&domain.PublishedShift{}, p.ID).Error; err != nil {
+					return err
+				}
+				continue
+			}
+			prev[k] = p
+
+			if _, ok := next[k]; !ok && !p.Cancelled {
+				if err := tx.Model(&domain.PublishedShift{}).Where("id = ?", p.ID).
+					Updates(map[string]interface{}{"cancelled": true, "sequence": p.Sequence + 1, "updated_at": now}).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		var created []domain.PublishedShift
+		for _, k := range order {
+			s := next[k]
+			p, ok := prev[k]
+			if !ok {
+				created = append(created, domain.PublishedShift{
+					PeriodID:  period.ID,
+					StaffID:   s.StaffID,
+					Date:      s.Date,
+					ShiftType: s.ShiftType,
+				})
+				continue
+			}
+			if p.Cancelled || p.ShiftType != s.ShiftType {
+				if err := tx.Model(&domain.PublishedShift{}).Where("id = ?", p.ID).
+					Updates(map[string]interface{}{"shift_type": s.ShiftType, "cancelled": false, "sequence": p.Sequence + 1, "updated_at": now}).Error; err != nil {
+					return err
+				}
+			}
+		}
+		if len(created) > 0 {
+			if err := tx.Create(&created).Error; err != nil {
+				return err
+			}
+		}
+
+		period.Status = domain.PeriodPublished
+		period.PublishedAt = &now
+		return tx.Model(period).Updates(map[string]interface{}{"status": period.Status, "published_at": now}).Error
+	})
+}
+
+// FindPublishedShifts: 公開済みのシフトを取得（取消になったものは除く）
+func (r *PeriodRepository) FindPublishedShifts(periodID uint) ([]domain.PublishedShift, error) {
+	var shifts []domain.PublishedShift
+	if err := r.db.Where("period_id = ? AND cancelled = ?", periodID, false).Order("date").Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
+
+// Delete: 期間と公開済みコピーを削除（シフト本体は残す）
+func (r *PeriodRepository) Delete(id int) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("period_id = ?", id).Delete(&domain.PublishedShift{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.SchedulePeriod{}, id).Error
+	})
+}
@@ -27,6 +27,24 @@ func NewDB() *gorm.DB {
         &domain.Shift{}, 
         &domain.ShiftRequest{}, 
         &domain.DailyRequirement{}, // ★これを追加！
+        &domain.StaffPairRule{},
+        &domain.DayOffRule{},
+        &domain.SchedulePeriod{},
+        &domain.PublishedShift{},
+        &domain.ScheduleVersion{},
+        &domain.VersionShift{},
+        &domain.AuditLog{},
+        &domain.RoleConstraint{},
+        &domain.ShiftSwap{},
+        &domain.SwapEvent{},
+        &domain.OpenShift{},
+        &domain.OpenShiftClaim{},
+        &domain.CallOut{},
+        &domain.Notification{},
+        &domain.NotificationPreference{},
+        &domain.CalendarToken{},
+        &domain.User{},
+        &domain.Session{},
     )
     
     if err != nil {
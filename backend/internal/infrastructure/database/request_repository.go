@@ -26,7 +26,54 @@ func (r *RequestRepository) FindAll() ([]domain.ShiftRequest, error) {
 	return reqs, nil
 }
 
+// FindByID: IDで1件取得
+func (r *RequestRepository) FindByID(id int) (*domain.ShiftRequest, error) {
+	var req domain.ShiftRequest
+	if err := r.db.First(&req, id).Error; err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// FindByStaffRange: スタッフの from〜to の休み希望
+func (r *RequestRepository) FindByStaffRange(staffID int, from string, to string) ([]domain.ShiftRequest, error) {
+	var reqs []domain.ShiftRequest
+	if err := r.db.Where("staff_id = ? AND date >= ? AND date <= ?", staffID, from, to).Find(&reqs).Error; err != nil {
+		return nil, err
+	}
+	return reqs, nil
+}
+
+// Review: 承認・却下の結果を保存（承認待ちのときだけ更新する）
+func (r *RequestRepository) Review(req *domain.ShiftRequest) error {
+	result := r.db.Model(&domain.ShiftRequest{}).
+		Where("id = ? AND status = ?", req.ID, domain.RequestPending).
+		Updates(map[string]interface{}{
+			"status":      req.Status,
+			"comment":     req.Comment,
+			"reviewed_by": req.ReviewedBy,
+			"reviewed_at": req.ReviewedAt,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidRequestStatus
+	}
+	return nil
+}
+
 // ★修正: id uint -> id int
 func (r *RequestRepository) Delete(id int) error {
 	return r.db.Delete(&domain.ShiftRequest{}, id).Error
-}
\ No newline at end of file
+}
+
+// SaveAll: まとめて登録する（1件でも失敗したら全部取り消す）
+func (r *RequestRepository) SaveAll(reqs []domain.ShiftRequest) error {
+	if len(reqs) == 0 {
+		return nil
+	}
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return tx.Create(&reqs).Error
+	})
+}
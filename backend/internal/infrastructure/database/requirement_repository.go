@@ -31,7 +31,40 @@ func (r *RequirementRepository) FindAll() ([]domain.DailyRequirement, error) {
 	return reqs, nil
 }
 
+// FindByID: IDで1件取得
+func (r *RequirementRepository) FindByID(id int) (*domain.DailyRequirement, error) {
+	var req domain.DailyRequirement
+	if err := r.db.First(&req, id).Error; err != nil {
+		return nil, err
+	}
+	return &req, nil
+}
+
+// FindByDate: 日付で1件取得（無ければ nil）
+func (r *RequirementRepository) FindByDate(date string) (*domain.DailyRequirement, error) {
+	var reqs []domain.DailyRequirement
+	if err := r.db.Where("date = ?", date).Limit(1).Find(&reqs).Error; err != nil {
+		return nil, err
+	}
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	return &reqs[0], nil
+}
+
 // ★追加: IDで削除
 func (r *RequirementRepository) Delete(id int) error {
 	return r.db.Delete(&domain.DailyRequirement{}, id).Error
-}
\ No newline at end of file
+}
+
+// SaveAll: まとめて登録・上書きする（ID が 0 なら登録、1件でも失敗したら全部取り消す）
+func (r *RequirementRepository) SaveAll(reqs []domain.DailyRequirement) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range reqs {
+			if err := tx.Save(&reqs[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,31 @@
+package database
+
+import (
+	"smart-shift-scheduler/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type RoleConstraintRepository struct {
+	db *gorm.DB
+}
+
+func NewRoleConstraintRepository(db *gorm.DB) *RoleConstraintRepository {
+	return &RoleConstraintRepository{db: db}
+}
+
+func (r *RoleConstraintRepository) Save(rc *domain.RoleConstraint) error {
+	return r.db.Create(rc).Error
+}
+
+func (r *RoleConstraintRepository) FindAll() ([]domain.RoleConstraint, error) {
+	var list []domain.RoleConstraint
+	if err := r.db.Find(&list).Error; err != nil {
+		return nil, err
+	}
+	return list, nil
+}
+
+func (r *RoleConstraintRepository) Delete(id int) error {
+	return r.db.Delete(&domain.RoleConstraint{}, id).Error
+}
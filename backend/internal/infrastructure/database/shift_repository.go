@@ -18,6 +18,10 @@ func NewShiftRepository(db *gorm.DB) *ShiftRepository {
 func (r *ShiftRepository) Save(shifts []domain.Shift) error {
     // 既存の同日・同スタッフのシフトがあれば削除してから保存するなどのロジックが必要だが
     // 簡易的にそのままCreate（重複回避はUniqueキー等で制御推奨）
+	// 全部ロック済みなどで保存するものが無い場合（空スライスのCreateはエラーになる）
+	if len(shifts) == 0 {
+		return nil
+	}
 	return r.db.Create(&shifts).Error
 }
 
@@ -29,6 +33,24 @@ func (r *ShiftRepository) FindAll() ([]domain.Shift, error) {
 	return shifts, nil
 }
 
+// FindRange: 期間内のシフトを日付順に取得
+func (r *ShiftRepository) FindRange(startDate string, endDate string) ([]domain.Shift, error) {
+	var shifts []domain.Shift
+	if err := r.db.Where("date >= ? AND date <= ?", startDate, endDate).Order("date").Find(&shifts).Error; err != nil {
+		return nil, err
+	}
+	return shifts, nil
+}
+
+// FindByID: IDで1件取得
+func (r *ShiftRepository) FindByID(id int) (*domain.Shift, error) {
+	var shift domain.Shift
+	if err := r.db.First(&shift, id).Error; err != nil {
+		return nil, err
+	}
+	return &shift, nil
+}
+
 func (r *ShiftRepository) Update(shift *domain.Shift) error {
 	// 指定したフィールドのみ更新（Dateなど）
 	return r.db.Model(shift).Updates(shift).Error
@@ -43,6 +65,69 @@ func (r *ShiftRepository) Delete(id int) error {
 func (r *ShiftRepository) DeleteByStaffID(staffID int) error {
 	return r.db.Where("staff_id = ?", staffID).Delete(&domain.Shift{}).Error
 }
+// DeleteRange: 期間内のシフトを削除（ロック済みのシフトは残す）
 func (r *ShiftRepository) DeleteRange(startDate string, endDate string) error {
-	return r.db.Where("date >= ? AND date <= ?", startDate, endDate).Delete(&domain.Shift{}).Error
-}
\ No newline at end of file
+	return r.db.Where("date >= ? AND date <= ? AND locked = ?", startDate, endDate, false).Delete(&domain.Shift{}).Error
+}
+
+// ReplaceRange: 期間内のシフトをロック済みも含めて入れ替える（巻き戻し用、1トランザクション）
+func (r *ShiftRepository) ReplaceRange(startDate string, endDate string, shifts []domain.Shift) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("date >= ? AND date <= ?", startDate, endDate).Delete(&domain.Shift{}).Error; err != nil {
+			return err
+		}
+		if len(shifts) == 0 {
+			return nil
+		}
+		return tx.Create(&shifts).Error
+	})
+}
+
+// SaveGenerated: 生成結果で期間を置き換える（1トランザクション）
+// ロックしていないシフトを消して shifts を保存し、生成で埋まらなかった募集中の枠も openShifts に入れ替える
+func (r *ShiftRepository) SaveGenerated(startDate string, endDate string, shifts []domain.Shift, openShifts []domain.OpenShift) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("date >= ? AND date <= ? AND locked = ?", startDate, endDate, false).Delete(&domain.Shift{}).Error; err != nil {
+			return err
+		}
+		if len(shifts) > 0 {
+			if err := tx.Create(&shifts).Error; err != nil {
+				return err
+			}
+		}
+		return replaceGeneratedOpenShifts(tx, startDate, endDate, openShifts)
+	})
+}
+
+// SetLocked: ロック状態だけを更新（Updatesだとfalseが無視されるため個別に更新する）
+func (r *ShiftRepository) SetLocked(id int, locked bool) error {
+	return r.db.Model(&domain.Shift{}).Where("id = ?", id).Update("locked", locked).Error
+}
+// Each: 条件に合うシフトを日付順に1件ずつ読む（全件をメモリに載せない）
+func (r *ShiftRepository) Each(filter domain.ShiftFilter, fn func(domain.Shift) error) error {
+	query := r.db.Model(&domain.Shift{})
+	if filter.From != "" {
+		query = query.Where("date >= ?", filter.From)
+	}
+	if filter.To != "" {
+		query = query.Where("date <= ?", filter.To)
+	}
+	if len(filter.StaffIDs) > 0 {
+		query = query.Where("staff_id IN ?", filter.StaffIDs)
+	}
+	rows, err := query.Order("date, staff_id, shift_type").Rows()
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var shift domain.Shift
+		if err := r.db.ScanRows(rows, &shift); err != nil {
+			return err
+		}
+		if err := fn(shift); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
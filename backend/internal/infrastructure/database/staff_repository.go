@@ -27,6 +27,15 @@ func (r *StaffRepository) FindAll() ([]domain.Staff, error) {
 	return staffList, nil
 }
 
+// FindByID: IDで1件取得
+func (r *StaffRepository) FindByID(id uint) (*domain.Staff, error) {
+	var staff domain.Staff
+	if err := r.db.First(&staff, id).Error; err != nil {
+		return nil, err
+	}
+	return &staff, nil
+}
+
 // Delete: スタッフとその人のシフトを削除（★ここを修正！）
 func (r *StaffRepository) Delete(id uint) error {
 	// 1. まず、そのスタッフIDに紐づくシフトを全削除する
@@ -35,6 +44,36 @@ func (r *StaffRepository) Delete(id uint) error {
 		return err // シフト削除に失敗したらエラーを返す
 	}
 
-	// 2. シフトが消えたら、スタッフ本人を削除する
+	// 2. そのスタッフが関わるペアルールも削除する
+	if err := r.db.Where("staff_id = ? OR partner_id = ?", id, id).Delete(&domain.StaffPairRule{}).Error; err != nil {
+		return err
+	}
+
+	// 3. スタッフ個別の休日ルールも削除する
+	if err := r.db.Where("staff_id = ?", id).Delete(&domain.DayOffRule{}).Error; err != nil {
+		return err
+	}
+
+	// 4. ログインアカウント（とそのセッション）も削除する
+	if err := r.db.Where("user_id IN (?)", r.db.Model(&domain.User{}).Select("id").Where("staff_id = ?", id)).Delete(&domain.Session{}).Error; err != nil {
+		return err
+	}
+	if err := r.db.Where("staff_id = ?", id).Delete(&domain.User{}).Error; err != nil {
+		return err
+	}
+
+	// 5. シフトが消えたら、スタッフ本人を削除する
 	return r.db.Delete(&domain.Staff{}, id).Error
-}
\ No newline at end of file
+}
+
+// SaveAll: まとめて登録・更新する（ID が 0 なら登録、1件でも失敗したら全部取り消す）
+func (r *StaffRepository) SaveAll(staff []domain.Staff) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i := range staff {
+			if err := tx.Save(&staff[i]).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
@@ -0,0 +1,114 @@
+package database
+
+import (
+	"errors"
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type SwapRepository struct {
+	db *gorm.DB
+}
+
+func NewSwapRepository(db *gorm.DB) *SwapRepository {
+	return &SwapRepository{db: db}
+}
+
+// Create: 交換申請と最初の履歴を保存
+func (r *SwapRepository) Create(swap *domain.ShiftSwap, event *domain.SwapEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(swap).Error; err != nil {
+			return err
+		}
+		event.SwapID = swap.ID
+		return tx.Create(event).Error
+	})
+}
+
+// Find: 交換申請の一覧（status, staffID は空・0なら絞り込まない。staffID は申請者・相手のどちらでも一致）
+func (r *SwapRepository) Find(status string, staffID int) ([]domain.ShiftSwap, error) {
+	query := r.db.Order("id DESC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	if staffID != 0 {
+		query = query.Where("requester_id = ? OR target_id = ?", staffID, staffID)
+	}
+	var swaps []domain.ShiftSwap
+	if err := query.Find(&swaps).Error; err != nil {
+		return nil, err
+	}
+	return swaps, nil
+}
+
+// FindByID: 履歴込みで取得
+func (r *SwapRepository) FindByID(id int) (*domain.ShiftSwap, error) {
+	var swap domain.ShiftSwap
+	err := r.db.Preload("History", func(db *gorm.DB) *gorm.DB { return db.Order("id") }).First(&swap, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &swap, nil
+}
+
+// FindOpenByShift: そのシフトを含む、まだ終わっていない交換申請
+func (r *SwapRepository) FindOpenByShift(shiftID uint) ([]domain.ShiftSwap, error) {
+	var swaps []domain.ShiftSwap
+	err := r.db.Where("status IN ?", []string{domain.SwapProposed, domain.SwapAccepted}).
+		Where("shift_id = ? OR target_shift_id = ?", shiftID, shiftID).
+		Find(&swaps).Error
+	if err != nil {
+		return nil, err
+	}
+	return swaps, nil
+}
+
+// UpdateStatus: 状態を変えて履歴を追加（event.FromStatus の状態のときだけ更新する）
+func (r *SwapRepository) UpdateStatus(swap *domain.ShiftSwap, event *domain.SwapEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		return updateSwapStatus(tx, swap, event)
+	})
+}
+
+// Apply: シフトの付け替えと状態の更新を1つのトランザクションで行う
+// before の各シフトを after のスタッフに付け替える。申請後にシフトが変わっていたら何も更新しない
+func (r *SwapRepository) Apply(swap *domain.ShiftSwap, before []domain.Shift, after []domain.Shift, event *domain.SwapEvent) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		for i, b := range before {
+			result := tx.Model(&domain.Shift{}).
+				Where("id = ? AND staff_id = ? AND date = ? AND shift_type = ?", b.ID, b.StaffID, b.Date, b.ShiftType).
+				Update("staff_id", after[i].StaffID)
+			if result.Error != nil {
+				return result.Error
+			}
+			if result.RowsAffected == 0 {
+				return fmt.Errorf("%w: シフト(ID:%d)が申請後に変更されています", domain.ErrInvalidSwapStatus, b.ID)
+			}
+		}
+		return updateSwapStatus(tx, swap, event)
+	})
+}
+
+func updateSwapStatus(tx *gorm.DB, swap *domain.ShiftSwap, event *domain.SwapEvent) error {
+	result := tx.Model(&domain.ShiftSwap{}).
+		Where("id = ? AND status = ?", swap.ID, event.FromStatus).
+		Update("status", event.ToStatus)
+	if result.Error != nil {
+		return result.Error
+	}
+	// 同時に別の操作で状態が変わっていた
+	if result.RowsAffected == 0 {
+		return domain.ErrInvalidSwapStatus
+	}
+	event.SwapID = swap.ID
+	if err := tx.Create(event).Error; err != nil {
+		return err
+	}
+	swap.Status = event.ToStatus
+	return nil
+}
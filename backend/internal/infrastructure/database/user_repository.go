@@ -0,0 +1,104 @@
+package database
+
+import (
+	"errors"
+	"smart-shift-scheduler/internal/domain"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+type UserRepository struct {
+	db *gorm.DB
+}
+
+func NewUserRepository(db *gorm.DB) *UserRepository {
+	return &UserRepository{db: db}
+}
+
+func (r *UserRepository) FindAll() ([]domain.User, error) {
+	var users []domain.User
+	if err := r.db.Order("id").Find(&users).Error; err != nil {
+		return nil, err
+	}
+	return users, nil
+}
+
+func (r *UserRepository) FindByID(id uint) (*domain.User, error) {
+	return r.first(r.db.Where("id = ?", id))
+}
+
+func (r *UserRepository) FindByUsername(username string) (*domain.User, error) {
+	return r.first(r.db.Where("username = ?", username))
+}
+
+// FindByStaff: スタッフに紐づくアカウント（無ければ nil）
+func (r *UserRepository) FindByStaff(staffID int) (*domain.User, error) {
+	user, err := r.first(r.db.Where("staff_id = ?", staffID))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, nil
+	}
+	return user, err
+}
+
+func (r *UserRepository) first(query *gorm.DB) (*domain.User, error) {
+	var user domain.User
+	err := query.First(&user).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func (r *UserRepository) Count() (int64, error) {
+	var count int64
+	err := r.db.Model(&domain.User{}).Count(&count).Error
+	return count, err
+}
+
+func (r *UserRepository) Save(user *domain.User) error {
+	return r.db.Save(user).Error
+}
+
+// Delete: アカウントとそのセッションを削除
+func (r *UserRepository) Delete(id uint) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", id).Delete(&domain.Session{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&domain.User{}, id).Error
+	})
+}
+
+func (r *UserRepository) CreateSession(session *domain.Session) error {
+	return r.db.Create(session).Error
+}
+
+func (r *UserRepository) FindSession(tokenHash string) (*domain.Session, error) {
+	var session domain.Session
+	err := r.db.Where("token_hash = ?", tokenHash).First(&session).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (r *UserRepository) DeleteSession(tokenHash string) error {
+	return r.db.Where("token_hash = ?", tokenHash).Delete(&domain.Session{}).Error
+}
+
+// DeleteSessions: その人のセッションをすべて削除（パスワードを変えたとき）
+func (r *UserRepository) DeleteSessions(userID uint) error {
+	return r.db.Where("user_id = ?", userID).Delete(&domain.Session{}).Error
+}
+
+// DeleteExpiredSessions: 期限切れのセッションを削除
+func (r *UserRepository) DeleteExpiredSessions(now time.Time) error {
+	return r.db.Where("expires_at < ?", now).Delete(&domain.Session{}).Error
+}
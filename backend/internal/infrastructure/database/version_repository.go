@@ -0,0 +1,54 @@
+package database
+
+import (
+	"errors"
+	"smart-shift-scheduler/internal/domain"
+
+	"gorm.io/gorm"
+)
+
+type VersionRepository struct {
+	db *gorm.DB
+}
+
+func NewVersionRepository(db *gorm.DB) *VersionRepository {
+	return &VersionRepository{db: db}
+}
+
+// Create: スナップショットを保存（バージョン番号は期間ごとの連番を振る）
+func (r *VersionRepository) Create(version *domain.ScheduleVersion) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var latest int
+		if err := tx.Model(&domain.ScheduleVersion{}).
+			Where("period_id = ?", version.PeriodID).
+			Select("COALESCE(MAX(version), 0)").
+			Scan(&latest).Error; err != nil {
+			return err
+		}
+		version.Version = latest + 1
+		// Shifts も一緒に保存される
+		return tx.Create(version).Error
+	})
+}
+
+// FindByPeriod: 期間のバージョン一覧（シフト本体は含めない）
+func (r *VersionRepository) FindByPeriod(periodID uint) ([]domain.ScheduleVersion, error) {
+	var versions []domain.ScheduleVersion
+	if err := r.db.Where("period_id = ?", periodID).Order("version").Find(&versions).Error; err != nil {
+		return nil, err
+	}
+	return versions, nil
+}
+
+// FindByNumber: 期間とバージョン番号で取得（シフト込み）
+func (r *VersionRepository) FindByNumber(periodID uint, number int) (*domain.ScheduleVersion, error) {
+	var version domain.ScheduleVersion
+	err := r.db.Preload("Shifts").Where("period_id = ? AND version = ?", periodID, number).First(&version).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, domain.ErrNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &version, nil
+}
@@ -0,0 +1,43 @@
+package notifier
+
+import (
+	"log"
+	"smart-shift-scheduler/internal/domain"
+	"sync"
+)
+
+// FakeSender: 送らずにメモリに記録する（開発・テスト用）
+type FakeSender struct {
+	mu   sync.Mutex
+	sent []domain.Notification
+	fail error // nil でなければ送信を失敗させる
+}
+
+func NewFakeSender() *FakeSender {
+	return &FakeSender{}
+}
+
+func (s *FakeSender) Send(n domain.Notification) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.fail != nil {
+		return s.fail
+	}
+	s.sent = append(s.sent, n)
+	log.Printf("[通知] %s -> staff %d (%s): %s", n.Event, n.StaffID, n.Channel, n.Subject)
+	return nil
+}
+
+// Sent: これまでに送ったことにした通知
+func (s *FakeSender) Sent() []domain.Notification {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]domain.Notification{}, s.sent...)
+}
+
+// FailWith: 以降の送信を err で失敗させる（nil で元に戻す）
+func (s *FakeSender) FailWith(err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.fail = err
+}
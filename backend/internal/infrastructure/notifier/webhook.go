@@ -0,0 +1,68 @@
+package notifier
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"smart-shift-scheduler/internal/domain"
+	"time"
+)
+
+// WebhookSender: 登録された URL に JSON を POST する
+type WebhookSender struct {
+	client *http.Client
+	secret string // 空でなければ本文の HMAC-SHA256 を X-Signature に付ける
+}
+
+func NewWebhookSender(secret string) *WebhookSender {
+	return &WebhookSender{client: &http.Client{Timeout: 10 * time.Second}, secret: secret}
+}
+
+func (s *WebhookSender) Send(n domain.Notification) error {
+	if n.Address == "" {
+		return fmt.Errorf("Webhook の URL が登録されていません")
+	}
+	payload := map[string]interface{}{
+		"id":         n.ID,
+		"event":      n.Event,
+		"staff_id":   n.StaffID,
+		"subject":    n.Subject,
+		"body":       n.Body,
+		"created_at": n.CreatedAt,
+	}
+	if n.Data != "" {
+		payload["data"] = json.RawMessage(n.Data)
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequest(http.MethodPost, n.Address, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Event", n.Event)
+	if s.secret != "" {
+		mac := hmac.New(sha256.New, []byte(s.secret))
+		mac.Write(body)
+		req.Header.Set("X-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("Webhook の応答が %d でした", resp.StatusCode)
+	}
+	return nil
+}
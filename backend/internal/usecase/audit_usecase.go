@@ -0,0 +1,45 @@
+package usecase
+
+import (
+	"encoding/json"
+	"smart-shift-scheduler/internal/domain"
+)
+
+type AuditRepository interface {
+	Save(log *domain.AuditLog) error
+	Find(filter domain.AuditFilter) ([]domain.AuditLog, error)
+}
+
+// AuditUsecase: 変更履歴の記録と検索
+type AuditUsecase struct {
+	repo AuditRepository
+}
+
+func NewAuditUsecase(repo AuditRepository) *AuditUsecase {
+	return &AuditUsecase{repo: repo}
+}
+
+// Record: 変更前・変更後の値をJSONにして記録する（nil の側は空のまま）
+func (u *AuditUsecase) Record(log domain.AuditLog, before interface{}, after interface{}) error {
+	if before != nil {
+		b, err := json.Marshal(before)
+		if err != nil {
+			return err
+		}
+		log.Before = string(b)
+	}
+	if after != nil {
+		a, err := json.Marshal(after)
+		if err != nil {
+			return err
+		}
+		log.After = string(a)
+	}
+	log.ID = 0
+	return u.repo.Save(&log)
+}
+
+// Search: 条件で検索
+func (u *AuditUsecase) Search(filter domain.AuditFilter) ([]domain.AuditLog, error) {
+	return u.repo.Find(filter)
+}
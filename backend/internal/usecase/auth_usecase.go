@@ -0,0 +1,316 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+type UserRepository interface {
+	FindAll() ([]domain.User, error)
+	FindByID(id uint) (*domain.User, error)
+	FindByUsername(username string) (*domain.User, error)
+	FindByStaff(staffID int) (*domain.User, error) // 無ければ nil
+	Count() (int64, error)
+	Save(user *domain.User) error
+	Delete(id uint) error // セッションも消す
+	CreateSession(session *domain.Session) error
+	FindSession(tokenHash string) (*domain.Session, error)
+	DeleteSession(tokenHash string) error
+	DeleteSessions(userID uint) error
+	DeleteExpiredSessions(now time.Time) error
+}
+
+const (
+	sessionTTL        = 7 * 24 * time.Hour
+	minPasswordLength = 8
+)
+
+// dummyHash: ユーザー名が無いときも照合にかかる時間を揃えるためのハッシュ（存在するユーザー名を探られないように）
+var dummyHash, _ = bcrypt.GenerateFromPassword([]byte("smart-shift-scheduler"), bcrypt.DefaultCost)
+
+// UserInput: アカウントの作成・変更の内容（変更では空・nil の項目は変えない）
+type UserInput struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+	Role     string `json:"role"`
+	StaffID  *int   `json:"staff_id"`
+}
+
+// AuthUsecase: ログインとアカウントの管理
+type AuthUsecase struct {
+	repo      UserRepository
+	staffRepo StaffRepository
+}
+
+func NewAuthUsecase(repo UserRepository, staffRepo StaffRepository) *AuthUsecase {
+	return &AuthUsecase{repo: repo, staffRepo: staffRepo}
+}
+
+// Bootstrap: アカウントが1件も無いときだけ、最初のオーナーを作る（起動時に環境変数から）
+// アカウントが無いのにユーザー名も無ければ、誰もログインできないのでエラーにする
+func (u *AuthUsecase) Bootstrap(username string, password string) (bool, error) {
+	count, err := u.repo.Count()
+	if err != nil || count > 0 {
+		return false, err
+	}
+	if username == "" {
+		return false, fmt.Errorf("%w: アカウントが1件もありません。OWNER_USERNAME と OWNER_PASSWORD を設定して起動してください", domain.ErrInvalidInput)
+	}
+	owner := domain.User{Username: username, Role: domain.RoleOwner}
+	if err := setPassword(&owner, password); err != nil {
+		return false, err
+	}
+	if err := u.repo.Save(&owner); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// Login: ユーザー名とパスワードを確かめて、セッションを作る
+func (u *AuthUsecase) Login(username string, password string) (*domain.LoginResult, error) {
+	user, err := u.repo.FindByUsername(strings.TrimSpace(username))
+	if errors.Is(err, domain.ErrNotFound) {
+		bcrypt.CompareHashAndPassword(dummyHash, []byte(password))
+		return nil, fmt.Errorf("%w: ユーザー名かパスワードが正しくありません", domain.ErrUnauthorized)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(password)) != nil {
+		return nil, fmt.Errorf("%w: ユーザー名かパスワードが正しくありません", domain.ErrUnauthorized)
+	}
+	if err := u.repo.DeleteExpiredSessions(time.Now()); err != nil {
+		return nil, err
+	}
+	return u.startSession(user)
+}
+
+// Logout: セッションを消す
+func (u *AuthUsecase) Logout(token string) error {
+	return u.repo.DeleteSession(hashToken(token))
+}
+
+// Authenticate: セッションの合言葉からログイン中のユーザーを引く
+func (u *AuthUsecase) Authenticate(token string) (*domain.User, error) {
+	if token == "" {
+		return nil, domain.ErrUnauthorized
+	}
+	session, err := u.repo.FindSession(hashToken(token))
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrUnauthorized
+	}
+	if err != nil {
+		return nil, err
+	}
+	if time.Now().After(session.ExpiresAt) {
+		u.repo.DeleteSession(session.TokenHash)
+		return nil, fmt.Errorf("%w: セッションの有効期限が切れています", domain.ErrUnauthorized)
+	}
+	user, err := u.repo.FindByID(session.UserID)
+	if errors.Is(err, domain.ErrNotFound) {
+		return nil, domain.ErrUnauthorized
+	}
+	return user, err
+}
+
+// ChangePassword: 自分のパスワードを変える（他の端末のセッションは切れ、新しいセッションを返す）
+func (u *AuthUsecase) ChangePassword(user *domain.User, current string, next string) (*domain.LoginResult, error) {
+	if bcrypt.CompareHashAndPassword([]byte(user.PasswordHash), []byte(current)) != nil {
+		return nil, fmt.Errorf("%w: 今のパスワードが正しくありません", domain.ErrInvalidInput)
+	}
+	if err := setPassword(user, next); err != nil {
+		return nil, err
+	}
+	if err := u.repo.Save(user); err != nil {
+		return nil, err
+	}
+	if err := u.repo.DeleteSessions(user.ID); err != nil {
+		return nil, err
+	}
+	return u.startSession(user)
+}
+
+// ListUsers: アカウントの一覧
+func (u *AuthUsecase) ListUsers() ([]domain.User, error) {
+	return u.repo.FindAll()
+}
+
+// CreateUser: アカウントを作る（店長が作れるのはスタッフのアカウントだけ）
+func (u *AuthUsecase) CreateUser(actor *domain.User, input UserInput) (*domain.User, error) {
+	user := domain.User{Username: strings.TrimSpace(input.Username), Role: input.Role}
+	if user.Username == "" {
+		return nil, fmt.Errorf("%w: ユーザー名を入力してください", domain.ErrInvalidInput)
+	}
+	if user.Role == "" {
+		user.Role = domain.RoleStaff
+	}
+	if input.StaffID != nil {
+		user.StaffID = *input.StaffID
+	}
+	if err := u.checkUser(actor, &user); err != nil {
+		return nil, err
+	}
+	if err := setPassword(&user, input.Password); err != nil {
+		return nil, err
+	}
+	if err := u.repo.Save(&user); err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+// UpdateUser: 権限・紐づけるスタッフ・パスワードを変える（パスワードを変えたらその人のセッションは切れる）
+func (u *AuthUsecase) UpdateUser(actor *domain.User, id uint, input UserInput) (before *domain.User, after *domain.User, err error) {
+	user, err := u.repo.FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !actor.CanManage(user.Role) {
+		return nil, nil, fmt.Errorf("%w: %s のアカウントは変更できません", domain.ErrForbidden, user.Role)
+	}
+	prev := *user
+	if input.Username != "" {
+		user.Username = strings.TrimSpace(input.Username)
+	}
+	if input.Role != "" {
+		user.Role = input.Role
+	}
+	if input.StaffID != nil {
+		user.StaffID = *input.StaffID
+	}
+	if prev.Role == domain.RoleOwner && user.Role != domain.RoleOwner {
+		if err := u.checkNotLastOwner(user.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := u.checkUser(actor, user); err != nil {
+		return nil, nil, err
+	}
+	if input.Password != "" {
+		if err := setPassword(user, input.Password); err != nil {
+			return nil, nil, err
+		}
+	}
+	if err := u.repo.Save(user); err != nil {
+		return nil, nil, err
+	}
+	if input.Password != "" {
+		if err := u.repo.DeleteSessions(user.ID); err != nil {
+			return nil, nil, err
+		}
+	}
+	return &prev, user, nil
+}
+
+// DeleteUser: アカウントを消す（自分自身と、最後のオーナーは消せない）
+func (u *AuthUsecase) DeleteUser(actor *domain.User, id uint) (*domain.User, error) {
+	user, err := u.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !actor.CanManage(user.Role) {
+		return nil, fmt.Errorf("%w: %s のアカウントは削除できません", domain.ErrForbidden, user.Role)
+	}
+	if user.ID == actor.ID {
+		return nil, fmt.Errorf("%w: 自分のアカウントは削除できません", domain.ErrInvalidInput)
+	}
+	if user.Role == domain.RoleOwner {
+		if err := u.checkNotLastOwner(user.ID); err != nil {
+			return nil, err
+		}
+	}
+	if err := u.repo.Delete(id); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// checkUser: 権限・ユーザー名・紐づけるスタッフを確かめる
+func (u *AuthUsecase) checkUser(actor *domain.User, user *domain.User) error {
+	if !domain.ValidRole(user.Role) {
+		return fmt.Errorf("%w: role は %s, %s, %s のいずれかを指定してください", domain.ErrInvalidInput, domain.RoleOwner, domain.RoleManager, domain.RoleStaff)
+	}
+	if !actor.CanManage(user.Role) {
+		return fmt.Errorf("%w: %s の権限は付けられません", domain.ErrForbidden, user.Role)
+	}
+	if user.Username == "" {
+		return fmt.Errorf("%w: ユーザー名を入力してください", domain.ErrInvalidInput)
+	}
+	if other, err := u.repo.FindByUsername(user.Username); err == nil && other.ID != user.ID {
+		return fmt.Errorf("%w: ユーザー名 %s はすでに使われています", domain.ErrInvalidInput, user.Username)
+	} else if err != nil && !errors.Is(err, domain.ErrNotFound) {
+		return err
+	}
+
+	// スタッフの権限は、本人のシフト・申請を扱うのでスタッフとの紐づけが要る
+	if user.StaffID == 0 {
+		if user.Role == domain.RoleStaff {
+			return fmt.Errorf("%w: %s のアカウントには staff_id が必要です", domain.ErrInvalidInput, domain.RoleStaff)
+		}
+		return nil
+	}
+	if _, err := u.staffRepo.FindByID(uint(user.StaffID)); err != nil {
+		return fmt.Errorf("%w: スタッフ %d が見つかりません", domain.ErrInvalidInput, user.StaffID)
+	}
+	other, err := u.repo.FindByStaff(user.StaffID)
+	if err != nil {
+		return err
+	}
+	if other != nil && other.ID != user.ID {
+		return fmt.Errorf("%w: スタッフ %d にはすでにアカウントがあります（%s）", domain.ErrInvalidInput, user.StaffID, other.Username)
+	}
+	return nil
+}
+
+// checkNotLastOwner: オーナーが1人もいなくならないようにする
+func (u *AuthUsecase) checkNotLastOwner(id uint) error {
+	users, err := u.repo.FindAll()
+	if err != nil {
+		return err
+	}
+	for _, other := range users {
+		if other.Role == domain.RoleOwner && other.ID != id {
+			return nil
+		}
+	}
+	return fmt.Errorf("%w: オーナーのアカウントが1件は必要です", domain.ErrInvalidInput)
+}
+
+func (u *AuthUsecase) startSession(user *domain.User) (*domain.LoginResult, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	token := hex.EncodeToString(buf)
+	session := &domain.Session{TokenHash: hashToken(token), UserID: user.ID, ExpiresAt: time.Now().Add(sessionTTL)}
+	if err := u.repo.CreateSession(session); err != nil {
+		return nil, err
+	}
+	return &domain.LoginResult{Token: token, ExpiresAt: session.ExpiresAt, User: *user}, nil
+}
+
+// setPassword: パスワードを bcrypt のハッシュにして持たせる
+func setPassword(user *domain.User, password string) error {
+	if len([]rune(password)) < minPasswordLength {
+		return fmt.Errorf("%w: パスワードは%d文字以上にしてください", domain.ErrInvalidInput, minPasswordLength)
+	}
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return fmt.Errorf("%w: %v", domain.ErrInvalidInput, err) // 72バイトを超えるパスワードなど
+	}
+	user.PasswordHash = string(hash)
+	return nil
+}
+
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,179 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"time"
+)
+
+type BackupRepository interface {
+	Dump() (*domain.BackupData, error)
+	Restore(data *domain.BackupData) (unlinked []string, err error)
+}
+
+// BackupUsecase: データ全体のバックアップと復元
+type BackupUsecase struct {
+	repo BackupRepository
+}
+
+func NewBackupUsecase(repo BackupRepository) *BackupUsecase {
+	return &BackupUsecase{repo: repo}
+}
+
+// Export: 今のデータをすべて書き出す
+func (u *BackupUsecase) Export() (*domain.Backup, error) {
+	data, err := u.repo.Dump()
+	if err != nil {
+		return nil, err
+	}
+	return &domain.Backup{Format: domain.BackupFormat, Version: domain.BackupVersion, CreatedAt: time.Now(), Data: *data}, nil
+}
+
+// Restore: バックアップで今のデータを置き換える（1トランザクション。失敗したら何も変わらない）
+// ID は振り直し、スタッフ・期間・シフトへの参照も新しい ID に読み替える
+// 削除済みのスタッフ・期間を指している行は復元しない（元の環境でも画面に出ていなかったもの）
+// ログインアカウントと監査ログは残し、スタッフへの参照を社員番号・名前で復元後のスタッフに付け替える
+func (u *BackupUsecase) Restore(backup *domain.Backup, dryRun bool) (*domain.RestoreResult, error) {
+	if backup.Format != domain.BackupFormat {
+		return nil, fmt.Errorf("%w: %s のファイルではありません", domain.ErrInvalidInput, domain.BackupFormat)
+	}
+	if backup.Version < 1 || backup.Version > domain.BackupVersion {
+		return nil, fmt.Errorf("%w: バックアップのバージョン %d には対応していません（このサーバーが読めるのは %d まで）", domain.ErrInvalidInput, backup.Version, domain.BackupVersion)
+	}
+
+	data := backup.Data
+	skipped := pruneOrphans(&data)
+	if err := checkBackupUnique(&data); err != nil {
+		return nil, err
+	}
+
+	result := &domain.RestoreResult{DryRun: dryRun, Version: backup.Version, Restored: backupCounts(&data), Skipped: skipped}
+	if dryRun {
+		return result, nil
+	}
+	unlinked, err := u.repo.Restore(&data)
+	if err != nil {
+		return nil, err
+	}
+	result.UnlinkedUsers = unlinked
+	return result, nil
+}
+
+// pruneOrphans: 存在しないスタッフ・期間を指す行を除く（0 は「指していない」なのでそのまま）
+func pruneOrphans(data *domain.BackupData) map[string]int {
+	staff := make(map[int]bool)
+	for _, s := range data.Staff {
+		staff[int(s.ID)] = true
+	}
+	periods := make(map[uint]bool)
+	for _, p := range data.Periods {
+		periods[p.ID] = true
+	}
+	hasStaff := func(ids ...int) bool {
+		for _, id := range ids {
+			if id != 0 && !staff[id] {
+				return false
+			}
+		}
+		return true
+	}
+	skipped := make(map[string]int)
+	keep := func(table string, ok bool) bool {
+		if !ok {
+			skipped[table]++
+		}
+		return ok
+	}
+
+	data.Shifts = filter(data.Shifts, func(s domain.Shift) bool { return keep("shifts", hasStaff(s.StaffID)) })
+	data.Requests = filter(data.Requests, func(r domain.ShiftRequest) bool { return keep("requests", hasStaff(r.StaffID)) })
+	data.PairRules = filter(data.PairRules, func(r domain.StaffPairRule) bool {
+		return keep("pair_rules", hasStaff(r.StaffID, r.PartnerID))
+	})
+	data.DayOffRules = filter(data.DayOffRules, func(r domain.DayOffRule) bool { return keep("day_off_rules", hasStaff(r.StaffID)) })
+	data.PublishedShifts = filter(data.PublishedShifts, func(s domain.PublishedShift) bool {
+		return keep("published_shifts", periods[s.PeriodID] && hasStaff(s.StaffID))
+	})
+	data.Versions = filter(data.Versions, func(v domain.ScheduleVersion) bool { return keep("versions", periods[v.PeriodID]) })
+	for i := range data.Versions {
+		data.Versions[i].Shifts = filter(data.Versions[i].Shifts, func(s domain.VersionShift) bool {
+			return keep("version_shifts", hasStaff(s.StaffID))
+		})
+	}
+	data.Swaps = filter(data.Swaps, func(s domain.ShiftSwap) bool { return keep("swaps", hasStaff(s.RequesterID, s.TargetID)) })
+	data.OpenShifts = filter(data.OpenShifts, func(o domain.OpenShift) bool {
+		return keep("open_shifts", hasStaff(o.DroppedBy, o.FilledBy))
+	})
+	for i := range data.OpenShifts {
+		data.OpenShifts[i].Claims = filter(data.OpenShifts[i].Claims, func(c domain.OpenShiftClaim) bool {
+			return keep("open_shift_claims", hasStaff(c.StaffID))
+		})
+	}
+	data.CallOuts = filter(data.CallOuts, func(c domain.CallOut) bool {
+		return keep("call_outs", hasStaff(c.AbsentStaffID, c.ReplacementID))
+	})
+	data.NotificationPreferences = filter(data.NotificationPreferences, func(p domain.NotificationPreference) bool {
+		return keep("notification_preferences", hasStaff(p.StaffID))
+	})
+	return skipped
+}
+
+// checkBackupUnique: 一意でなければならない列の重複（途中で保存に失敗しないよう先に確かめる）
+func checkBackupUnique(data *domain.BackupData) error {
+	dates := make(map[string]bool)
+	for _, r := range data.Requirements {
+		if dates[r.Date] {
+			return fmt.Errorf("%w: %s の必要人数が2回出てきます", domain.ErrInvalidInput, r.Date)
+		}
+		dates[r.Date] = true
+	}
+	prefs := make(map[int]bool)
+	for _, p := range data.NotificationPreferences {
+		if prefs[p.StaffID] {
+			return fmt.Errorf("%w: スタッフ %d の通知設定が2回出てきます", domain.ErrInvalidInput, p.StaffID)
+		}
+		prefs[p.StaffID] = true
+	}
+	return nil
+}
+
+// backupCounts: テーブルごとの件数
+func backupCounts(data *domain.BackupData) map[string]int {
+	counts := map[string]int{
+		"staff":                    len(data.Staff),
+		"periods":                  len(data.Periods),
+		"shifts":                   len(data.Shifts),
+		"requests":                 len(data.Requests),
+		"requirements":             len(data.Requirements),
+		"pair_rules":               len(data.PairRules),
+		"day_off_rules":            len(data.DayOffRules),
+		"role_constraints":         len(data.RoleConstraints),
+		"published_shifts":         len(data.PublishedShifts),
+		"versions":                 len(data.Versions),
+		"swaps":                    len(data.Swaps),
+		"open_shifts":              len(data.OpenShifts),
+		"call_outs":                len(data.CallOuts),
+		"notification_preferences": len(data.NotificationPreferences),
+	}
+	for _, v := range data.Versions {
+		counts["version_shifts"] += len(v.Shifts)
+	}
+	for _, s := range data.Swaps {
+		counts["swap_events"] += len(s.History)
+	}
+	for _, o := range data.OpenShifts {
+		counts["open_shift_claims"] += len(o.Claims)
+	}
+	return counts
+}
+
+// filter: 条件に合う要素だけ残す
+func filter[T any](list []T, ok func(T) bool) []T {
+	var kept []T
+	for _, v := range list {
+		if ok(v) {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"strings"
+	"time"
+)
+
+type CalendarRepository interface {
+	FindToken(staffID int) (*domain.CalendarToken, error)
+	FindByToken(token string) (*domain.CalendarToken, error)
+	SaveToken(token *domain.CalendarToken) error
+	FindPublishedShifts(staffID int) ([]domain.PublishedShift, error)
+}
+
+const (
+	icalTimezone = "Asia/Tokyo"
+	icalDomain   = "smart-shift-scheduler" // UID の @ 以降
+)
+
+// CalendarUsecase: スタッフごとのカレンダー購読（iCalendar 形式）
+type CalendarUsecase struct {
+	repo       CalendarRepository
+	staffRepo  StaffRepository
+	periodRepo PeriodRepository
+}
+
+func NewCalendarUsecase(repo CalendarRepository, staffRepo StaffRepository, periodRepo PeriodRepository) *CalendarUsecase {
+	return &CalendarUsecase{repo: repo, staffRepo: staffRepo, periodRepo: periodRepo}
+}
+
+// Token: スタッフの合言葉（未発行なら発行する）
+func (u *CalendarUsecase) Token(staffID int) (*domain.CalendarToken, error) {
+	token, err := u.repo.FindToken(staffID)
+	if err != nil {
+		return nil, err
+	}
+	if token != nil {
+		return token, nil
+	}
+	return u.RotateToken(staffID)
+}
+
+// RotateToken: 合言葉を作り直す（URLが漏れたとき用。古いURLは使えなくなる）
+func (u *CalendarUsecase) RotateToken(staffID int) (*domain.CalendarToken, error) {
+	if _, err := u.staffRepo.FindByID(uint(staffID)); err != nil {
+		return nil, domain.ErrNotFound
+	}
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return nil, err
+	}
+	token := &domain.CalendarToken{StaffID: staffID, Token: hex.EncodeToString(buf), CreatedAt: time.Now()}
+	if err := u.repo.SaveToken(token); err != nil {
+		return nil, err
+	}
+	return token, nil
+}
+
+// Feed: 合言葉のスタッフの公開済みシフトを iCalendar にする
+func (u *CalendarUsecase) Feed(token string) (string, error) {
+	t, err := u.repo.FindByToken(token)
+	if err != nil {
+		return "", err
+	}
+	staff, err := u.staffRepo.FindByID(uint(t.StaffID))
+	if err != nil {
+		return "", domain.ErrNotFound
+	}
+	shifts, err := u.repo.FindPublishedShifts(t.StaffID)
+	if err != nil {
+		return "", err
+	}
+	periods, err := u.periodRepo.FindAll()
+	if err != nil {
+		return "", err
+	}
+	stores := make(map[uint]string)
+	for _, p := range periods {
+		stores[p.ID] = p.StoreName
+	}
+	return renderICal(*staff, shifts, stores, time.Now()), nil
+}
+
+// renderICal: 1シフト1予定。UID はスタッフ×日付で固定し、再公開での変更は SEQUENCE を上げて上書きさせる
+func renderICal(staff domain.Staff, shifts []domain.PublishedShift, stores map[uint]string, now time.Time) string {
+	var b strings.Builder
+	line := func(s string) { b.WriteString(foldICalLine(s) + "\r\n") }
+
+	line("BEGIN:VCALENDAR")
+	line("VERSION:2.0")
+	line("PRODID:-//" + icalDomain + "//shift calendar//JA")
+	line("CALSCALE:GREGORIAN")
+	line("METHOD:PUBLISH")
+	line("X-WR-CALNAME:" + escapeICal("シフト（"+staff.Name+"）"))
+	line("X-WR-TIMEZONE:" + icalTimezone)
+	line("REFRESH-INTERVAL;VALUE=DURATION:PT1H")
+	line("BEGIN:VTIMEZONE")
+	line("TZID:" + icalTimezone)
+	line("BEGIN:STANDARD")
+	line("DTSTART:19700101T000000")
+	line("TZOFFSETFROM:+0900")
+	line("TZOFFSETTO:+0900")
+	line("TZNAME:JST")
+	line("END:STANDARD")
+	line("END:VTIMEZONE")
+
+	for _, s := range shifts {
+		t, ok := domain.FindShiftTemplate(s.ShiftType)
+		if !ok {
+			continue
+		}
+		start, err1 := time.Parse("2006-01-02 15:04", s.Date+" "+t.Start)
+		end, err2 := time.Parse("2006-01-02 15:04", s.Date+" "+t.End)
+		if err1 != nil || err2 != nil {
+			continue
+		}
+		if !end.After(start) {
+			end = end.AddDate(0, 0, 1) // 日をまたぐシフト
+		}
+		stamp := s.UpdatedAt
+		if stamp.IsZero() {
+			stamp = now
+		}
+
+		line("BEGIN:VEVENT")
+		line(fmt.Sprintf("UID:shift-%d-%s@%s", s.StaffID, s.Date, icalDomain))
+		line("DTSTAMP:" + stamp.UTC().Format("20060102T150405Z"))
+		line("LAST-MODIFIED:" + stamp.UTC().Format("20060102T150405Z"))
+		line("DTSTART;TZID=" + icalTimezone + ":" + start.Format("20060102T150405"))
+		line("DTEND;TZID=" + icalTimezone + ":" + end.Format("20060102T150405"))
+		line(fmt.Sprintf("SEQUENCE:%d", s.Sequence))
+		line("SUMMARY:" + escapeICal(t.Name))
+		if store := stores[s.PeriodID]; store != "" {
+			line("LOCATION:" + escapeICal(store))
+		}
+		description := fmt.Sprintf("%s %s-%s", t.Name, t.Start, t.End)
+		if t.BreakMinutes > 0 {
+			description += fmt.Sprintf("（休憩%d分）", t.BreakMinutes)
+		}
+		line("DESCRIPTION:" + escapeICal(description))
+		if s.Cancelled {
+			line("STATUS:CANCELLED")
+		} else {
+			line("STATUS:CONFIRMED")
+		}
+		line("TRANSP:OPAQUE")
+		line("END:VEVENT")
+	}
+
+	line("END:VCALENDAR")
+	return b.String()
+}
+
+// escapeICal: TEXT 型のエスケープ（\ ; , 改行）
+func escapeICal(s string) string {
+	return strings.NewReplacer(`\`, `\\`, ";", `\;`, ",", `\,`, "\r\n", `\n`, "\n", `\n`).Replace(s)
+}
+
+// foldICalLine: 75オクテットごとに折り返す（マルチバイト文字の途中では切らない）
+func foldICalLine(s string) string {
+	var b strings.Builder
+	width := 0
+	for _, r := range s {
+		n := len(string(r))
+		if width+n > 75 {
+			b.WriteString("\r\n ")
+			width = 1
+		}
+		b.WriteRune(r)
+		width += n
+	}
+	return b.String()
+}
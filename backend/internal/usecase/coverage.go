@@ -0,0 +1,144 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"sort"
+	"time"
+)
+
+// Coverage: from〜to の日付×シフト区分ごとの必要人数・配置人数（ヒートマップ用）
+func (u *ShiftUsecase) Coverage(from string, to string) ([]domain.CoverageDay, error) {
+	if err := checkDateRange(from, to); err != nil {
+		return nil, err
+	}
+	shifts, err := u.shiftRepo.FindRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := u.loadRuleContext()
+	if err != nil {
+		return nil, err
+	}
+	return rc.coverage(shifts, from, to), nil
+}
+
+// coverage: 日付ごとの配置状況を集計する
+// 必要人数は DailyRequirement（無ければデフォルト）、役割はRoleConstraint（1日単位）で判定する
+func (rc *ruleContext) coverage(shifts []domain.Shift, from string, to string) []domain.CoverageDay {
+	byDate := make(map[string][]domain.Shift)
+	for _, s := range shifts {
+		byDate[s.Date] = append(byDate[s.Date], s)
+	}
+
+	days := []domain.CoverageDay{}
+	for date := from; date <= to; date = addDays(date, 1) {
+		t, _ := time.Parse(dateLayout, date)
+		day := domain.CoverageDay{Date: date, Weekday: int(t.Weekday()), Slots: []domain.CoverageSlot{}, Roles: []domain.RoleCoverage{}}
+		list := byDate[date]
+		sort.Slice(list, func(i, j int) bool { return list[i].StaffID < list[j].StaffID })
+
+		for _, tmpl := range domain.ShiftTemplates {
+			slot := domain.CoverageSlot{
+				ShiftType: tmpl.Type,
+				ShiftName: tmpl.Name,
+				Required:  rc.need(date, tmpl.Type),
+				StaffIDs:  []int{},
+				Roles:     make(map[string]int),
+			}
+			for _, s := range list {
+				if s.ShiftType != tmpl.Type {
+					continue
+				}
+				slot.Scheduled++
+				slot.StaffIDs = append(slot.StaffIDs, s.StaffID)
+				for _, role := range rc.roles {
+					if rc.hasRole(s.StaffID, role.Role) {
+						slot.Roles[role.Role]++
+					}
+				}
+			}
+			slot.Delta = slot.Scheduled - slot.Required
+			slot.Status = domain.CoverageStatus(slot.Delta)
+			day.Slots = append(day.Slots, slot)
+		}
+
+		for _, role := range rc.roles {
+			got := 0
+			for _, slot := range day.Slots {
+				got += slot.Roles[role.Role]
+			}
+			day.Roles = append(day.Roles, domain.RoleCoverage{
+				Role:      role.Role,
+				Required:  role.Count,
+				Scheduled: got,
+				Delta:     got - role.Count,
+				Status:    domain.CoverageStatus(got - role.Count),
+			})
+		}
+		days = append(days, day)
+	}
+	return days
+}
+
+// CoverageCSVRows: ヒートマップをCSVの行にする（1行 = 日付×シフト区分）
+func CoverageCSVRows(days []domain.CoverageDay) [][]string {
+	// 役割の列は、出てきた順に並べる
+	var roles []string
+	seen := make(map[string]bool)
+	for _, day := range days {
+		for _, r := range day.Roles {
+			if !seen[r.Role] {
+				seen[r.Role] = true
+				roles = append(roles, r.Role)
+			}
+		}
+	}
+
+	header := []string{"日付", "曜日", "シフト", "必要人数", "配置人数", "過不足", "状態"}
+	for _, role := range roles {
+		header = append(header, role+"(この枠)", role+"(1日/必要)")
+	}
+	rows := [][]string{header}
+
+	weekdays := []string{"日", "月", "火", "水", "木", "金", "土"}
+	for _, day := range days {
+		dayRoles := make(map[string]domain.RoleCoverage)
+		for _, r := range day.Roles {
+			dayRoles[r.Role] = r
+		}
+		for _, slot := range day.Slots {
+			row := []string{
+				day.Date,
+				weekdays[day.Weekday],
+				slot.ShiftName,
+				fmt.Sprint(slot.Required),
+				fmt.Sprint(slot.Scheduled),
+				fmt.Sprintf("%+d", slot.Delta),
+				slot.Status,
+			}
+			for _, role := range roles {
+				r := dayRoles[role]
+				row = append(row, fmt.Sprint(slot.Roles[role]), fmt.Sprintf("%d/%d", r.Scheduled, r.Required))
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// checkDateRange: from/to が YYYY-MM-DD で、from <= to であること
+func checkDateRange(from string, to string) error {
+	start, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return fmt.Errorf("%w: from は YYYY-MM-DD で指定してください", domain.ErrInvalidInput)
+	}
+	end, err := time.Parse(dateLayout, to)
+	if err != nil {
+		return fmt.Errorf("%w: to は YYYY-MM-DD で指定してください", domain.ErrInvalidInput)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("%w: from は to 以前の日付を指定してください", domain.ErrInvalidInput)
+	}
+	return nil
+}
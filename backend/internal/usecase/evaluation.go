@@ -0,0 +1,177 @@
+package usecase
+
+import (
+	"math"
+	"smart-shift-scheduler/internal/domain"
+	"sort"
+)
+
+// Evaluate: from〜to の保存済みシフトを、ソルバーと同じルールで採点する
+// 手修正や取り込んだシフトも、自動生成と同じ物差しで確認できるようにする
+func (u *ShiftUsecase) Evaluate(from string, to string) (*domain.ScheduleEvaluation, error) {
+	if err := checkDateRange(from, to); err != nil {
+		return nil, err
+	}
+
+	// 連勤・週休の判定のため、前後1週間も読み込む
+	shifts, err := u.shiftRepo.FindRange(addDays(from, -7), addDays(to, 7))
+	if err != nil {
+		return nil, err
+	}
+	rc, err := u.loadRuleContext()
+	if err != nil {
+		return nil, err
+	}
+	requests, err := u.approvedRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	eval := &domain.ScheduleEvaluation{From: from, To: to, Violations: rc.check(shifts, from, to)}
+	if eval.Violations == nil {
+		eval.Violations = []domain.Violation{}
+	}
+	for _, v := range eval.Violations {
+		if v.Level == domain.ViolationHard {
+			eval.HardViolations++
+		} else {
+			eval.SoftViolations++
+		}
+	}
+
+	// 出勤している日（スタッフ×日付）
+	working := make(map[string]bool)
+	for _, s := range shifts {
+		if s.ShiftType != 0 {
+			working[cellKey(s.StaffID, s.Date)] = true
+		}
+	}
+
+	// 1. 必要人数・役割ごとの人数
+	for _, day := range rc.coverage(shifts, from, to) {
+		for _, slot := range day.Slots {
+			eval.Coverage.Required += slot.Required
+			eval.Coverage.Scheduled += slot.Scheduled
+			if slot.Delta < 0 {
+				eval.Coverage.Filled += slot.Scheduled
+				eval.Coverage.Shortage -= slot.Delta
+			} else {
+				eval.Coverage.Filled += slot.Required
+				eval.Coverage.Overstaffed += slot.Delta
+			}
+		}
+		for _, role := range day.Roles {
+			eval.RoleMinimum.Checked++
+			if role.Delta >= 0 {
+				eval.RoleMinimum.Met++
+			}
+		}
+	}
+	eval.Coverage.Rate = ratio(eval.Coverage.Filled, eval.Coverage.Required)
+	eval.RoleMinimum.Rate = ratio(eval.RoleMinimum.Met, eval.RoleMinimum.Checked)
+
+	// 2. 承認済みの休み希望が守られたか
+	for _, r := range requests {
+		if r.Date < from || r.Date > to {
+			continue
+		}
+		eval.Requests.Total++
+		if !working[cellKey(r.StaffID, r.Date)] {
+			eval.Requests.Honoured++
+		}
+	}
+	eval.Requests.Rate = ratio(eval.Requests.Honoured, eval.Requests.Total)
+
+	// 3. スタッフごとの勤務時間・有給・人件費・連勤
+	violationCount := make(map[int]int)
+	for _, v := range eval.Violations {
+		if v.StaffID != 0 {
+			violationCount[v.StaffID]++
+		}
+	}
+	kpis := make(map[int]*domain.StaffKPI)
+	costs := make(map[int]float64)
+	for id, st := range rc.staff {
+		kpis[id] = &domain.StaffKPI{StaffID: id, Name: st.Name, Violations: violationCount[id]}
+	}
+	for _, s := range shifts {
+		kpi, ok := kpis[s.StaffID]
+		if !ok || s.Date < from || s.Date > to {
+			continue
+		}
+		t, ok := domain.FindShiftTemplate(s.ShiftType)
+		if !ok {
+			continue
+		}
+		kpi.WorkDays++
+		kpi.Hours += t.Hours()
+		costs[s.StaffID] += t.Hours() * float64(rc.staff[s.StaffID].HourlyWage)
+	}
+	// 有給は所定労働時間分を給与に含める
+	for _, r := range requests {
+		kpi, ok := kpis[r.StaffID]
+		if !ok || r.Type != domain.RequestTypePaidLeave || r.Date < from || r.Date > to {
+			continue
+		}
+		hours := paidLeaveHoursPerDay(rc.staff[r.StaffID])
+		kpi.PaidLeaveDays++
+		kpi.PaidLeaveHours += hours
+		costs[r.StaffID] += hours * float64(rc.staff[r.StaffID].HourlyWage)
+	}
+	for id, kpi := range kpis {
+		kpi.Cost = int(math.Round(costs[id]))
+		// 期間前からの連勤も数える
+		streak := 0
+		for d := addDays(from, -7); d <= to; d = addDays(d, 1) {
+			if !working[cellKey(id, d)] {
+				streak = 0
+				continue
+			}
+			streak++
+			if d >= from && streak > kpi.MaxConsecutiveDays {
+				kpi.MaxConsecutiveDays = streak
+			}
+		}
+	}
+
+	eval.Staff = make([]domain.StaffKPI, 0, len(kpis))
+	for _, kpi := range kpis {
+		eval.Staff = append(eval.Staff, *kpi)
+	}
+	sort.Slice(eval.Staff, func(i, j int) bool { return eval.Staff[i].StaffID < eval.Staff[j].StaffID })
+
+	// 4. 全体の合計と公平性（出勤日数・時間の最大 - 最小）
+	minHours, maxHours := 0.0, 0.0
+	for i, kpi := range eval.Staff {
+		eval.TotalHours += kpi.Hours
+		eval.PaidLeaveHours += kpi.PaidLeaveHours
+		eval.LaborCost += kpi.Cost
+		if kpi.MaxConsecutiveDays > eval.MaxConsecutiveDays {
+			eval.MaxConsecutiveDays = kpi.MaxConsecutiveDays
+		}
+		if i == 0 || kpi.WorkDays < eval.Fairness.MinDays {
+			eval.Fairness.MinDays = kpi.WorkDays
+		}
+		if i == 0 || kpi.WorkDays > eval.Fairness.MaxDays {
+			eval.Fairness.MaxDays = kpi.WorkDays
+		}
+		if i == 0 || kpi.Hours < minHours {
+			minHours = kpi.Hours
+		}
+		if i == 0 || kpi.Hours > maxHours {
+			maxHours = kpi.Hours
+		}
+	}
+	eval.Fairness.DaysSpread = eval.Fairness.MaxDays - eval.Fairness.MinDays
+	eval.Fairness.HoursSpread = maxHours - minHours
+
+	return eval, nil
+}
+
+// ratio: a / b（b が0なら、満たすべきものが無いので1）
+func ratio(a int, b int) float64 {
+	if b == 0 {
+		return 1
+	}
+	return float64(a) / float64(b)
+}
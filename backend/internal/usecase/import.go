@@ -0,0 +1,121 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"smart-shift-scheduler/internal/domain"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// 取り込みファイルのセルの読み方（CSV・Excel 共通）
+
+// excelEpoch: Excel の日付シリアル値の起点（1900年のうるう年の扱いのずれを含めて 1899-12-30）
+var excelEpoch = time.Date(1899, 12, 30, 0, 0, 0, 0, time.UTC)
+
+// parseImportDate: "2026-02-10" / "2026/2/10" / Excel のシリアル値（46063）を YYYY-MM-DD にする
+func parseImportDate(v string) (string, error) {
+	for _, layout := range []string{dateLayout, "2006/1/2", "2006-1-2", "2006.1.2"} {
+		if t, err := time.Parse(layout, v); err == nil {
+			return t.Format(dateLayout), nil
+		}
+	}
+	if serial, err := strconv.ParseFloat(v, 64); err == nil && serial >= 1 && serial < 2958466 {
+		return excelEpoch.AddDate(0, 0, int(serial)).Format(dateLayout), nil
+	}
+	return "", errors.New("日付（YYYY-MM-DD）で入力してください")
+}
+
+// parseImportNumber: "1,100" / "1100円" / "1100.0" を数にする
+func parseImportNumber(v string) (float64, error) {
+	v = strings.NewReplacer(",", "", "，", "", "円", "", "¥", "", "￥", "").Replace(v)
+	n, err := strconv.ParseFloat(strings.TrimSpace(v), 64)
+	if err != nil || math.IsNaN(n) || math.IsInf(n, 0) {
+		return 0, errors.New("数値で入力してください")
+	}
+	return n, nil
+}
+
+// parseImportInt: 整数（0以上）
+func parseImportInt(v string) (int, error) {
+	n, err := parseImportNumber(v)
+	if err != nil || n != math.Trunc(n) {
+		return 0, errors.New("整数で入力してください")
+	}
+	if n < 0 {
+		return 0, errors.New("0以上で入力してください")
+	}
+	return int(n), nil
+}
+
+// parseImportBool: 1/0、true/false、yes/no、○/×、はい/いいえ
+func parseImportBool(v string) (bool, error) {
+	switch strings.ToLower(v) {
+	case "1", "true", "yes", "y", "○", "◯", "はい":
+		return true, nil
+	case "0", "false", "no", "n", "×", "いいえ", "-":
+		return false, nil
+	}
+	return false, errors.New("true/false（1/0）で入力してください")
+}
+
+// importErrors: 行ごとのエラーを集める
+type importErrors []domain.ImportRowError
+
+func (e *importErrors) add(line int, column string, message string) {
+	*e = append(*e, domain.ImportRowError{Line: line, Column: column, Message: message})
+}
+
+// staffResolver: 取り込みファイルのスタッフ欄（ID・社員番号・名前）から人を探す
+type staffResolver struct {
+	byID   map[int]domain.Staff
+	byCode map[string]domain.Staff
+	byName map[string][]domain.Staff
+}
+
+func newStaffResolver(staffList []domain.Staff) *staffResolver {
+	r := &staffResolver{byID: map[int]domain.Staff{}, byCode: map[string]domain.Staff{}, byName: map[string][]domain.Staff{}}
+	for _, s := range staffList {
+		r.byID[int(s.ID)] = s
+		if s.EmployeeCode != "" {
+			r.byCode[s.EmployeeCode] = s
+		}
+		key := nameKey(s.Name)
+		r.byName[key] = append(r.byName[key], s)
+	}
+	return r
+}
+
+// resolve: ID > 社員番号 > 名前 の順で探す（同じ名前が複数いれば社員番号を求める）
+func (r *staffResolver) resolve(id string, code string, name string) (domain.Staff, error) {
+	switch {
+	case id != "":
+		n, err := strconv.Atoi(id)
+		if s, ok := r.byID[n]; err == nil && ok {
+			return s, nil
+		}
+		return domain.Staff{}, fmt.Errorf("スタッフ ID %s が見つかりません", id)
+	case code != "":
+		if s, ok := r.byCode[code]; ok {
+			return s, nil
+		}
+		return domain.Staff{}, fmt.Errorf("社員番号 %s が見つかりません", code)
+	case name != "":
+		matches := r.byName[nameKey(name)]
+		if len(matches) == 1 {
+			return matches[0], nil
+		}
+		if len(matches) > 1 {
+			return domain.Staff{}, fmt.Errorf("%[2]s という名前のスタッフが %[1]d 人います（社員番号で指定してください）", len(matches), name)
+		}
+		return domain.Staff{}, fmt.Errorf("スタッフ %s が見つかりません", name)
+	}
+	return domain.Staff{}, errors.New("スタッフを指定してください（staff_id, employee_code, name のいずれか）")
+}
+
+// nameKey: 名前の比較用（姓名の間の空白は全角・半角とも無視する）
+func nameKey(name string) string {
+	return strings.Join(strings.Fields(strings.ReplaceAll(name, "　", " ")), "")
+}
@@ -0,0 +1,417 @@
+package usecase
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"smart-shift-scheduler/internal/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+type NotificationRepository interface {
+	Enqueue(list []domain.Notification) error
+	ClaimDue(now time.Time, lease time.Duration, limit int) ([]domain.Notification, error)
+	Find(status string, staffID int) ([]domain.Notification, error)
+	FindByID(id int) (*domain.Notification, error)
+	Update(n *domain.Notification) error
+	FindPreferences() ([]domain.NotificationPreference, error)
+	FindPreference(staffID int) (*domain.NotificationPreference, error)
+	SavePreference(pref *domain.NotificationPreference) error
+}
+
+// NotificationSender: 1件の通知を送る（メール・Webhook など送り先ごとに実装する）
+type NotificationSender interface {
+	Send(n domain.Notification) error
+}
+
+const (
+	notificationMaxAttempts = 5                // これだけ失敗したら再送をやめる
+	notificationBackoff     = time.Minute      // 1回目の失敗後の待ち時間（以降は倍々）
+	notificationMaxBackoff  = 1 * time.Hour    // 待ち時間の上限
+	notificationBatchSize   = 50               // 1回の送信処理で送る件数
+	notificationLease       = 10 * time.Minute // 送信中のまま結果が保存されなければ、この時間の後にもう一度送る
+)
+
+// NotificationUsecase: 通知をアウトボックスに積み、別のタイミングで送る
+// 積むのは各操作の成功後なので、送信に失敗しても元の操作には影響しない
+type NotificationUsecase struct {
+	repo       NotificationRepository
+	staffRepo  domain.StaffRepository
+	periodRepo PeriodRepository
+	senders    map[string]NotificationSender // domain.ChannelEmail などをキーにする
+}
+
+func NewNotificationUsecase(repo NotificationRepository, staffRepo domain.StaffRepository, periodRepo PeriodRepository, senders map[string]NotificationSender) *NotificationUsecase {
+	return &NotificationUsecase{repo: repo, staffRepo: staffRepo, periodRepo: periodRepo, senders: senders}
+}
+
+// SchedulePublished: 期間の公開を全スタッフに知らせる（本文は本人のシフトの一覧）
+func (u *NotificationUsecase) SchedulePublished(period *domain.SchedulePeriod) error {
+	published, err := u.periodRepo.FindPublishedShifts(period.ID)
+	if err != nil {
+		return err
+	}
+	staffList, err := u.staffRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	byStaff := make(map[int][]domain.PublishedShift)
+	for _, s := range published {
+		byStaff[s.StaffID] = append(byStaff[s.StaffID], s)
+	}
+
+	var ids []int
+	for _, s := range staffList {
+		ids = append(ids, int(s.ID))
+	}
+	subject := fmt.Sprintf("%s〜%sのシフトが公開されました", period.StartDate, period.EndDate)
+	data := map[string]interface{}{"period_id": period.ID, "start_date": period.StartDate, "end_date": period.EndDate}
+	return u.enqueue(domain.EventSchedulePublished, ids, subject, data, func(staffID int) string {
+		list := byStaff[staffID]
+		sort.Slice(list, func(i, j int) bool { return list[i].Date < list[j].Date })
+		var b strings.Builder
+		b.WriteString(subject + "\n\n")
+		count := 0
+		for _, s := range list {
+			if s.ShiftType == 0 {
+				continue
+			}
+			b.WriteString(shiftLine(s.Date, s.ShiftType) + "\n")
+			count++
+		}
+		if count == 0 {
+			b.WriteString("この期間のシフトはありません\n")
+		}
+		return b.String()
+	})
+}
+
+// ShiftChanged: 公開済みの期間のシフトが変わったことを、変更前後の本人に知らせる
+// before が nil なら追加、after が nil なら削除。下書きの期間の変更は知らせない
+func (u *NotificationUsecase) ShiftChanged(before *domain.Shift, after *domain.Shift) error {
+	var ids []int
+	published := false
+	for _, s := range []*domain.Shift{before, after} {
+		if s == nil {
+			continue
+		}
+		ids = append(ids, s.StaffID)
+		period, err := u.periodRepo.FindCovering(s.Date)
+		if err != nil {
+			return err
+		}
+		if period != nil && period.Status != domain.PeriodDraft {
+			published = true
+		}
+	}
+	if !published {
+		return nil
+	}
+
+	date := ""
+	if before != nil {
+		date = before.Date
+	} else if after != nil {
+		date = after.Date
+	}
+	subject := fmt.Sprintf("%sのシフトが変更されました", date)
+	data := map[string]interface{}{"before": before, "after": after}
+	return u.enqueue(domain.EventShiftChanged, ids, subject, data, func(staffID int) string {
+		var b strings.Builder
+		b.WriteString(subject + "\n\n")
+		if before != nil && before.StaffID == staffID {
+			b.WriteString("変更前: " + shiftLine(before.Date, before.ShiftType) + "\n")
+		}
+		if after != nil && after.StaffID == staffID {
+			b.WriteString("変更後: " + shiftLine(after.Date, after.ShiftType) + "\n")
+		} else {
+			b.WriteString("変更後: このシフトには入りません\n")
+		}
+		return b.String()
+	})
+}
+
+// SwapApproved: 交換の承認を申請者と相手に知らせる
+func (u *NotificationUsecase) SwapApproved(result *SwapApproval) error {
+	swap := result.Swap
+	subject := "シフト交換が承認されました"
+	data := map[string]interface{}{"swap_id": swap.ID, "before": result.Before, "after": result.After}
+	return u.enqueue(domain.EventSwapApproved, []int{swap.RequesterID, swap.TargetID}, subject, data, func(staffID int) string {
+		var b strings.Builder
+		b.WriteString(subject + "\n\n")
+		for i, before := range result.Before {
+			after := result.After[i]
+			switch staffID {
+			case before.StaffID:
+				b.WriteString("入らなくなったシフト: " + shiftLine(before.Date, before.ShiftType) + "\n")
+			case after.StaffID:
+				b.WriteString("入ることになったシフト: " + shiftLine(after.Date, after.ShiftType) + "\n")
+			}
+		}
+		return b.String()
+	})
+}
+
+// RequestReviewed: 休み希望の承認・却下を本人に知らせる
+func (u *NotificationUsecase) RequestReviewed(req *domain.ShiftRequest) error {
+	event, result := domain.EventRequestApproved, "承認"
+	if req.Status == domain.RequestRejected {
+		event, result = domain.EventRequestRejected, "却下"
+	}
+	kind := "休み希望"
+	if req.Type == domain.RequestTypePaidLeave {
+		kind = "有給休暇"
+	}
+	subject := fmt.Sprintf("%sの%sが%sされました", req.Date, kind, result)
+	data := map[string]interface{}{"request_id": req.ID, "date": req.Date, "type": req.Type, "status": req.Status, "comment": req.Comment}
+	return u.enqueue(event, []int{req.StaffID}, subject, data, func(int) string {
+		body := subject + "\n"
+		if req.Comment != "" {
+			body += "\nコメント: " + req.Comment + "\n"
+		}
+		return body
+	})
+}
+
+// enqueue: 受け取り設定に従って、スタッフ×送り先ごとに通知を積む
+func (u *NotificationUsecase) enqueue(event string, staffIDs []int, subject string, data interface{}, bodyOf func(staffID int) string) error {
+	prefs, err := u.repo.FindPreferences()
+	if err != nil {
+		return err
+	}
+	byStaff := make(map[int]domain.NotificationPreference)
+	for _, p := range prefs {
+		byStaff[p.StaffID] = p
+	}
+	payload, err := json.Marshal(data)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	seen := make(map[int]bool)
+	var list []domain.Notification
+	for _, staffID := range staffIDs {
+		pref, ok := byStaff[staffID]
+		if seen[staffID] || !ok || !subscribed(pref, event) {
+			continue
+		}
+		seen[staffID] = true
+		body := bodyOf(staffID)
+		for _, channel := range splitList(pref.Channels) {
+			list = append(list, domain.Notification{
+				StaffID:       staffID,
+				Event:         event,
+				Channel:       channel,
+				Address:       addressOf(pref, channel),
+				Subject:       subject,
+				Body:          body,
+				Data:          string(payload),
+				Status:        domain.NotificationPending,
+				NextAttemptAt: now,
+			})
+		}
+	}
+	return u.repo.Enqueue(list)
+}
+
+// Dispatch: 送る時刻になった通知を送る。失敗したら間隔を倍々に空けて再送する
+// 先に送信中にしてから送るので、定期処理と手動の送信が重なっても同じ通知を二重に送らない
+// 戻り値は送れた件数
+func (u *NotificationUsecase) Dispatch(now time.Time) (int, error) {
+	due, err := u.repo.ClaimDue(now, notificationLease, notificationBatchSize)
+	if err != nil {
+		return 0, err
+	}
+	sent := 0
+	for i := range due {
+		n := &due[i]
+		n.Attempts++
+		err := fmt.Errorf("送信方法 %s が設定されていません", n.Channel)
+		if sender, ok := u.senders[n.Channel]; ok {
+			err = sender.Send(*n)
+		}
+
+		if err == nil {
+			n.Status = domain.NotificationSent
+			n.SentAt = &now
+			n.LastError = ""
+			sent++
+		} else {
+			n.LastError = err.Error()
+			if n.Attempts >= notificationMaxAttempts {
+				n.Status = domain.NotificationFailed
+			} else {
+				n.Status = domain.NotificationPending
+				n.NextAttemptAt = now.Add(notificationRetryDelay(n.Attempts))
+			}
+		}
+		if err := u.repo.Update(n); err != nil {
+			return sent, err
+		}
+	}
+	return sent, nil
+}
+
+// Run: interval ごとに Dispatch する（ctx が終わるまで）
+func (u *NotificationUsecase) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if _, err := u.Dispatch(time.Now()); err != nil {
+				log.Printf("通知の送信処理に失敗しました: %v", err)
+			}
+		}
+	}
+}
+
+// Retry: 再送をやめた通知をもう一度送信待ちにする
+func (u *NotificationUsecase) Retry(id int) (*domain.Notification, error) {
+	n, err := u.repo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if n.Status != domain.NotificationFailed {
+		return nil, fmt.Errorf("%w: 再送できるのは送信に失敗した通知だけです", domain.ErrInvalidInput)
+	}
+	n.Status = domain.NotificationPending
+	n.Attempts = 0
+	n.NextAttemptAt = time.Now()
+	if err := u.repo.Update(n); err != nil {
+		return nil, err
+	}
+	return n, nil
+}
+
+func (u *NotificationUsecase) ListNotifications(status string, staffID int) ([]domain.Notification, error) {
+	return u.repo.Find(status, staffID)
+}
+
+// GetPreference: 未設定なら通知しない設定を返す
+func (u *NotificationUsecase) GetPreference(staffID int) (*domain.NotificationPreference, error) {
+	pref, err := u.repo.FindPreference(staffID)
+	if err != nil {
+		return nil, err
+	}
+	if pref == nil {
+		pref = &domain.NotificationPreference{StaffID: staffID}
+	}
+	return pref, nil
+}
+
+// SavePreference: 送り先と、受け取るきっかけを検証して保存
+func (u *NotificationUsecase) SavePreference(pref *domain.NotificationPreference) error {
+	staffList, err := u.staffRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, s := range staffList {
+		if int(s.ID) == pref.StaffID {
+			found = true
+		}
+	}
+	if !found {
+		return fmt.Errorf("%w: スタッフが見つかりません", domain.ErrInvalidInput)
+	}
+
+	channels := splitList(pref.Channels)
+	for _, channel := range channels {
+		switch channel {
+		case domain.ChannelEmail:
+			if !strings.Contains(pref.Email, "@") {
+				return fmt.Errorf("%w: メールで受け取るにはメールアドレスが必要です", domain.ErrInvalidInput)
+			}
+		case domain.ChannelWebhook:
+			if !strings.HasPrefix(pref.WebhookURL, "http://") && !strings.HasPrefix(pref.WebhookURL, "https://") {
+				return fmt.Errorf("%w: Webhook で受け取るには http(s) の URL が必要です", domain.ErrInvalidInput)
+			}
+		case domain.ChannelFake:
+		default:
+			return fmt.Errorf("%w: 送り先 %s は使えません（email, webhook, fake）", domain.ErrInvalidInput, channel)
+		}
+	}
+	events := splitList(pref.Events)
+	for _, event := range events {
+		switch event {
+		case domain.EventSchedulePublished, domain.EventShiftChanged, domain.EventSwapApproved,
+			domain.EventRequestApproved, domain.EventRequestRejected:
+		default:
+			return fmt.Errorf("%w: 通知のきっかけ %s はありません", domain.ErrInvalidInput, event)
+		}
+	}
+	pref.ID = 0
+	pref.Channels = strings.Join(channels, ",")
+	pref.Events = strings.Join(events, ",")
+	return u.repo.SavePreference(pref)
+}
+
+// notificationRetryDelay: attempts 回失敗した後の待ち時間（1分, 2分, 4分, ... 最大1時間）
+func notificationRetryDelay(attempts int) time.Duration {
+	delay := notificationBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+		if delay >= notificationMaxBackoff {
+			return notificationMaxBackoff
+		}
+	}
+	return delay
+}
+
+// subscribed: Events が空ならすべてのきっかけで受け取る
+func subscribed(pref domain.NotificationPreference, event string) bool {
+	events := splitList(pref.Events)
+	if len(events) == 0 {
+		return true
+	}
+	for _, e := range events {
+		if e == event {
+			return true
+		}
+	}
+	return false
+}
+
+func addressOf(pref domain.NotificationPreference, channel string) string {
+	switch channel {
+	case domain.ChannelEmail:
+		return pref.Email
+	case domain.ChannelWebhook:
+		return pref.WebhookURL
+	}
+	return ""
+}
+
+// splitList: "a, b,,c" -> [a b c]（重複は除く）
+func splitList(s string) []string {
+	var list []string
+	seen := make(map[string]bool)
+	for _, v := range strings.Split(s, ",") {
+		v = strings.TrimSpace(v)
+		if v != "" && !seen[v] {
+			seen[v] = true
+			list = append(list, v)
+		}
+	}
+	return list
+}
+
+// shiftLine: "2026-02-10(火) 早番 09:00-18:00"
+func shiftLine(date string, shiftType int) string {
+	weekday := ""
+	if t, err := time.Parse(dateLayout, date); err == nil {
+		weekday = "(" + []string{"日", "月", "火", "水", "木", "金", "土"}[t.Weekday()] + ")"
+	}
+	t, ok := domain.FindShiftTemplate(shiftType)
+	if !ok {
+		return date + weekday + " 休み"
+	}
+	return fmt.Sprintf("%s%s %s %s-%s", date, weekday, t.Name, t.Start, t.End)
+}
@@ -0,0 +1,271 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"sort"
+	"time"
+)
+
+type OpenShiftRepository interface {
+	Save(open *domain.OpenShift) error
+	Find(status string, from string, to string) ([]domain.OpenShift, error)
+	FindByID(id int) (*domain.OpenShift, error)
+	Drop(shift *domain.Shift, open *domain.OpenShift) error
+	AddClaim(claim *domain.OpenShiftClaim) error
+	Fill(open *domain.OpenShift, shift *domain.Shift, claimID uint) error
+	Close(id int) error
+}
+
+// OpenShiftUsecase: 募集シフト（埋まらなかった枠・手放されたシフト）への応募と決定
+type OpenShiftUsecase struct {
+	openRepo OpenShiftRepository
+	shifts   *ShiftUsecase // ルール判定・確定済み期間のチェックに使う
+}
+
+func NewOpenShiftUsecase(openRepo OpenShiftRepository, shifts *ShiftUsecase) *OpenShiftUsecase {
+	return &OpenShiftUsecase{openRepo: openRepo, shifts: shifts}
+}
+
+// PostOpenShift: 店長が募集シフトを追加する
+func (u *OpenShiftUsecase) PostOpenShift(open *domain.OpenShift) error {
+	if _, err := time.Parse(dateLayout, open.Date); err != nil {
+		return fmt.Errorf("%w: 日付形式エラー: %v", domain.ErrInvalidInput, err)
+	}
+	if _, ok := domain.FindShiftTemplate(open.ShiftType); open.ShiftType != 0 && !ok {
+		return fmt.Errorf("%w: shift_type が正しくありません", domain.ErrInvalidInput)
+	}
+	if err := checkClaimMode(open); err != nil {
+		return err
+	}
+	if err := u.shifts.checkDateNotLocked(open.Date); err != nil {
+		return err
+	}
+
+	open.ID = 0
+	open.Source = domain.OpenShiftFromManual
+	open.Status = domain.OpenShiftOpen
+	open.DroppedBy = 0
+	open.FilledBy = 0
+	open.ShiftID = 0
+	open.FilledAt = nil
+	open.Claims = nil
+	return u.openRepo.Save(open)
+}
+
+func (u *OpenShiftUsecase) ListOpenShifts(status string, from string, to string) ([]domain.OpenShift, error) {
+	return u.openRepo.Find(status, from, to)
+}
+
+func (u *OpenShiftUsecase) GetOpenShift(id int) (*domain.OpenShift, error) {
+	return u.openRepo.FindByID(id)
+}
+
+// GetShift: 手放す前のシフト（本人かどうかの確認に使う）
+func (u *OpenShiftUsecase) GetShift(id int) (*domain.Shift, error) {
+	shift, err := u.shifts.GetShift(id)
+	if err != nil {
+		return nil, domain.ErrNotFound
+	}
+	return shift, nil
+}
+
+// DropShift: シフトを手放して、同じ枠を募集シフトにする
+// シフトは代わりの人が決まるまで元の人のまま残す（決まらなければ元の人が出勤する）
+func (u *OpenShiftUsecase) DropShift(shiftID int, claimMode string, note string) (*domain.Shift, *domain.OpenShift, error) {
+	shift, err := u.shifts.shiftRepo.FindByID(shiftID)
+	if err != nil {
+		return nil, nil, domain.ErrNotFound
+	}
+	if err := u.shifts.checkDateNotLocked(shift.Date); err != nil {
+		return nil, nil, err
+	}
+
+	open := &domain.OpenShift{
+		Date:      shift.Date,
+		ShiftType: shift.ShiftType,
+		Source:    domain.OpenShiftFromDrop,
+		Status:    domain.OpenShiftOpen,
+		ClaimMode: claimMode,
+		DroppedBy: shift.StaffID,
+		ShiftID:   shift.ID,
+		Note:      note,
+	}
+	if err := checkClaimMode(open); err != nil {
+		return nil, nil, err
+	}
+	if err := u.openRepo.Drop(shift, open); err != nil {
+		return nil, nil, err
+	}
+	return shift, open, nil
+}
+
+// Claim: 募集シフトに応募する
+// 早い者勝ちならその場でシフトを作成し（手放されたシフトなら付け替え）、優先度順なら応募として記録する
+func (u *OpenShiftUsecase) Claim(id int, staffID int, shiftType int) (*domain.OpenShift, []domain.Violation, error) {
+	open, err := u.openRepo.FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if open.Status != domain.OpenShiftOpen {
+		return nil, nil, domain.ErrOpenShiftClosed
+	}
+	shift, violations, err := u.eligible(open, staffID, shiftType)
+	if err != nil {
+		return nil, violations, err
+	}
+
+	if open.ClaimMode == domain.ClaimPriority {
+		for _, c := range open.Claims {
+			if c.StaffID == staffID && c.Status == domain.ClaimPending {
+				return nil, nil, fmt.Errorf("%w: 応募済みです", domain.ErrInvalidInput)
+			}
+		}
+		claim := domain.OpenShiftClaim{OpenShiftID: open.ID, StaffID: staffID, ShiftType: shift.ShiftType, Status: domain.ClaimPending}
+		if err := u.openRepo.AddClaim(&claim); err != nil {
+			return nil, nil, err
+		}
+		open.Claims = append(open.Claims, claim)
+		return open, violations, nil
+	}
+
+	if err := u.openRepo.Fill(open, shift, 0); err != nil {
+		return nil, nil, err
+	}
+	if err := u.shifts.snapshotEdit("募集シフトの決定", open.Date); err != nil {
+		return nil, nil, err
+	}
+	return open, violations, nil
+}
+
+// Award: 優先度順の募集を決める
+// staffID を指定すればその人に、0なら応募者を優先度順に見て、ルールを満たす最初の人に決める
+// 優先度: 前後2週間の出勤日数が少ない人 → 応募が早い人
+func (u *OpenShiftUsecase) Award(id int, staffID int) (*domain.OpenShift, []domain.Violation, error) {
+	open, err := u.openRepo.FindByID(id)
+	if err != nil {
+		return nil, nil, err
+	}
+	if open.Status != domain.OpenShiftOpen {
+		return nil, nil, domain.ErrOpenShiftClosed
+	}
+
+	var candidates []domain.OpenShiftClaim
+	for _, c := range open.Claims {
+		if c.Status == domain.ClaimPending && (staffID == 0 || c.StaffID == staffID) {
+			candidates = append(candidates, c)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil, fmt.Errorf("%w: 応募者がいません", domain.ErrInvalidInput)
+	}
+
+	workDays, err := u.workDaysAround(open.Date)
+	if err != nil {
+		return nil, nil, err
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if workDays[candidates[i].StaffID] != workDays[candidates[j].StaffID] {
+			return workDays[candidates[i].StaffID] < workDays[candidates[j].StaffID]
+		}
+		return candidates[i].ID < candidates[j].ID
+	})
+
+	// 応募後に他のシフトが変わっているかもしれないので、決める前にもう一度判定する
+	var lastViolations []domain.Violation
+	var lastErr error
+	for _, c := range candidates {
+		shift, violations, err := u.eligible(open, c.StaffID, c.ShiftType)
+		if err != nil {
+			lastViolations, lastErr = violations, err
+			continue
+		}
+		if err := u.openRepo.Fill(open, shift, c.ID); err != nil {
+			return nil, nil, err
+		}
+		if err := u.shifts.snapshotEdit("募集シフトの決定", open.Date); err != nil {
+			return nil, nil, err
+		}
+		return open, violations, nil
+	}
+	return nil, lastViolations, lastErr
+}
+
+// CloseOpenShift: 募集を取り下げる
+func (u *OpenShiftUsecase) CloseOpenShift(id int) error {
+	return u.openRepo.Close(id)
+}
+
+// eligible: staffID がこの枠に入れるか（役割・確定済み期間・ルール違反）
+// 入れるなら作成するシフトを返す（手放されたシフトなら、ID はそのシフトで担当者だけ替える）
+func (u *OpenShiftUsecase) eligible(open *domain.OpenShift, staffID int, shiftType int) (*domain.Shift, []domain.Violation, error) {
+	if open.ShiftType != 0 {
+		shiftType = open.ShiftType
+	}
+	if _, ok := domain.FindShiftTemplate(shiftType); !ok {
+		return nil, nil, fmt.Errorf("%w: shift_type を指定してください", domain.ErrInvalidInput)
+	}
+	if staffID == open.DroppedBy {
+		return nil, nil, fmt.Errorf("%w: 自分が手放したシフトには応募できません", domain.ErrInvalidInput)
+	}
+	if err := u.shifts.checkDateNotLocked(open.Date); err != nil {
+		return nil, nil, err
+	}
+
+	rc, err := u.shifts.loadRuleContext()
+	if err != nil {
+		return nil, nil, err
+	}
+	if _, ok := rc.staff[staffID]; !ok {
+		return nil, nil, fmt.Errorf("%w: スタッフが見つかりません", domain.ErrInvalidInput)
+	}
+	if open.Role != "" && !rc.hasRole(staffID, open.Role) {
+		return nil, nil, fmt.Errorf("%w: この枠には%sの役割が必要です", domain.ErrInvalidInput, open.Role)
+	}
+
+	shift := &domain.Shift{StaffID: staffID, Date: open.Date, ShiftType: shiftType}
+	var removed []domain.Shift
+	if open.ShiftID != 0 {
+		dropped, err := u.shifts.shiftRepo.FindByID(int(open.ShiftID))
+		if err != nil || dropped.StaffID != open.DroppedBy {
+			return nil, nil, domain.ErrShiftChanged
+		}
+		shift.ID = dropped.ID
+		removed = append(removed, *dropped)
+	}
+	violations, err := u.shifts.checkChange(removed, []domain.Shift{*shift})
+	if err != nil {
+		return nil, nil, err
+	}
+	if hasHard(violations) {
+		return nil, violations, domain.ErrRuleViolation
+	}
+	return shift, violations, nil
+}
+
+// workDaysAround: date の前後2週間のスタッフごとの出勤日数
+func (u *OpenShiftUsecase) workDaysAround(date string) (map[int]int, error) {
+	shifts, err := u.shifts.shiftRepo.FindRange(addDays(date, -14), addDays(date, 14))
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[int]int)
+	for _, s := range shifts {
+		if s.ShiftType != 0 {
+			counts[s.StaffID]++
+		}
+	}
+	return counts, nil
+}
+
+// checkClaimMode: 決め方の既定値は早い者勝ち
+func checkClaimMode(open *domain.OpenShift) error {
+	switch open.ClaimMode {
+	case "":
+		open.ClaimMode = domain.ClaimFirstCome
+	case domain.ClaimFirstCome, domain.ClaimPriority:
+	default:
+		return fmt.Errorf("%w: claim_mode は first_come か priority を指定してください", domain.ErrInvalidInput)
+	}
+	return nil
+}
@@ -0,0 +1,279 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"sort"
+	"time"
+)
+
+// 労働基準法39条の付与日数（勤続 0.5年, 1.5年, 2.5年, 3.5年, 4.5年, 5.5年, 6.5年以上）
+var (
+	paidLeaveFullTime = []int{10, 11, 12, 14, 16, 18, 20}
+	// 比例付与（週30時間未満かつ週4日以下）: 週の所定労働日数ごと
+	paidLeaveProportional = map[int][]int{
+		4: {7, 8, 9, 10, 12, 13, 15},
+		3: {5, 6, 6, 8, 9, 10, 11},
+		2: {3, 4, 4, 5, 6, 6, 7},
+		1: {1, 2, 2, 2, 3, 3, 3},
+	}
+)
+
+const (
+	paidLeaveValidYears     = 2  // 付与から2年で時効
+	paidLeaveObligationDays = 5  // 年5日の取得義務
+	paidLeaveObligationMin  = 10 // 取得義務の対象になる付与日数
+	paidLeaveExpiringDays   = 90 // この日数以内に失効するものを「失効間近」とする
+)
+
+// paidLeaveEntitlement: n回目（0始まり）の付与日数
+// 出勤率8割以上を満たしている前提。週の所定日数・時間は現在の設定で計算する
+func paidLeaveEntitlement(staff domain.Staff, n int) int {
+	if n > 6 {
+		n = 6
+	}
+	days := staff.WeeklyScheduledDays
+	if days <= 0 || days >= 5 || staff.WeeklyScheduledHours >= 30 {
+		return paidLeaveFullTime[n]
+	}
+	return paidLeaveProportional[days][n]
+}
+
+// paidLeaveHoursPerDay: 有給1日分として支払う時間（週の所定労働時間 ÷ 所定日数、未設定なら早番1日分）
+func paidLeaveHoursPerDay(staff domain.Staff) float64 {
+	if staff.WeeklyScheduledDays > 0 && staff.WeeklyScheduledHours > 0 {
+		return staff.WeeklyScheduledHours / float64(staff.WeeklyScheduledDays)
+	}
+	if t, ok := domain.FindShiftTemplate(1); ok {
+		return t.Hours()
+	}
+	return 8
+}
+
+// paidLeaveBalance: asOf 時点の付与・取得・残日数を計算する
+// taken は承認済みの有給の日付。古い付与から順に消化する
+func paidLeaveBalance(staff domain.Staff, taken []string, pending int, asOf string) (*domain.PaidLeaveBalance, error) {
+	balance := &domain.PaidLeaveBalance{
+		StaffID:     int(staff.ID),
+		Name:        staff.Name,
+		AsOf:        asOf,
+		Pending:     pending,
+		Grants:      []domain.PaidLeaveGrant{},
+		Obligations: []domain.PaidLeaveObligation{},
+		Warnings:    []string{},
+		HoursPerDay: paidLeaveHoursPerDay(staff),
+	}
+	if staff.HireDate == "" {
+		balance.Warnings = append(balance.Warnings, "入社日が未登録のため付与日数を計算できません")
+		balance.Unallocated = len(taken)
+		return balance, nil
+	}
+	hire, err := time.Parse(dateLayout, staff.HireDate)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 入社日の形式が正しくありません", domain.ErrInvalidInput)
+	}
+
+	// 1. 付与（入社6か月後、以降1年ごと）
+	for n := 0; ; n++ {
+		grantDate := hire.AddDate(0, 6+12*n, 0).Format(dateLayout)
+		if grantDate > asOf {
+			break
+		}
+		days := paidLeaveEntitlement(staff, n)
+		balance.Grants = append(balance.Grants, domain.PaidLeaveGrant{
+			GrantDate: grantDate,
+			ExpiresOn: addDays(addYears(grantDate, paidLeaveValidYears), -1),
+			Days:      days,
+			Remaining: days,
+		})
+	}
+
+	// 2. 取得日を古い付与から消化する
+	sort.Strings(taken)
+	for _, date := range taken {
+		used := false
+		for i := range balance.Grants {
+			g := &balance.Grants[i]
+			if g.GrantDate <= date && date <= g.ExpiresOn && g.Remaining > 0 {
+				g.Taken++
+				g.Remaining--
+				used = true
+				break
+			}
+		}
+		if !used {
+			balance.Unallocated++
+		}
+	}
+
+	// 3. 残日数・失効
+	soon := addDays(asOf, paidLeaveExpiringDays)
+	for i := range balance.Grants {
+		g := &balance.Grants[i]
+		if g.ExpiresOn < asOf {
+			g.Expired = true
+			continue
+		}
+		balance.Remaining += g.Remaining
+		if g.Remaining > 0 {
+			if balance.NextExpiry == "" || g.ExpiresOn < balance.NextExpiry {
+				balance.NextExpiry = g.ExpiresOn
+			}
+			if g.ExpiresOn <= soon {
+				balance.Expiring += g.Remaining
+			}
+		}
+	}
+	if balance.Expiring > 0 {
+		balance.Warnings = append(balance.Warnings, fmt.Sprintf("%d日分が%sに失効します", balance.Expiring, balance.NextExpiry))
+	}
+	if balance.Unallocated > 0 {
+		balance.Warnings = append(balance.Warnings, fmt.Sprintf("付与日数を%d日超えて取得しています", balance.Unallocated))
+	}
+
+	// 4. 年5日の取得義務（付与日から1年以内に、どの付与分からでも5日）
+	for _, g := range balance.Grants {
+		if g.Days < paidLeaveObligationMin {
+			continue
+		}
+		deadline := addDays(addYears(g.GrantDate, 1), -1)
+		ob := domain.PaidLeaveObligation{GrantDate: g.GrantDate, Deadline: deadline}
+		for _, date := range taken {
+			if g.GrantDate <= date && date <= deadline {
+				ob.Taken++
+			}
+		}
+		ob.Shortfall = paidLeaveObligationDays - ob.Taken
+		switch {
+		case ob.Shortfall <= 0:
+			ob.Shortfall = 0
+			ob.Status = domain.ObligationMet
+		case deadline < asOf:
+			ob.Status = domain.ObligationMissed
+			balance.Warnings = append(balance.Warnings, fmt.Sprintf("%s付与分の年5日の取得義務を満たせませんでした（%d日取得）", g.GrantDate, ob.Taken))
+		default:
+			ob.Status = domain.ObligationAtRisk
+			balance.Warnings = append(balance.Warnings, fmt.Sprintf("%sまでにあと%d日の有給取得が必要です（年5日の取得義務）", deadline, ob.Shortfall))
+		}
+		balance.Obligations = append(balance.Obligations, ob)
+	}
+	return balance, nil
+}
+
+// PaidLeaveBalances: 全スタッフの有給休暇の状況（asOf が空なら今日）
+func (u *ShiftUsecase) PaidLeaveBalances(asOf string) ([]domain.PaidLeaveBalance, error) {
+	if asOf == "" {
+		asOf = time.Now().Format(dateLayout)
+	}
+	if _, err := time.Parse(dateLayout, asOf); err != nil {
+		return nil, fmt.Errorf("%w: as_of は YYYY-MM-DD で指定してください", domain.ErrInvalidInput)
+	}
+
+	staffList, err := u.staffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	requests, err := u.requestRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	balances := []domain.PaidLeaveBalance{}
+	for _, staff := range staffList {
+		taken, pending := paidLeaveDates(requests, int(staff.ID), asOf)
+		b, err := paidLeaveBalance(staff, taken, pending, asOf)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", staff.Name, err)
+		}
+		balances = append(balances, *b)
+	}
+	return balances, nil
+}
+
+// PaidLeaveBalance: スタッフ1人の有給休暇の状況
+func (u *ShiftUsecase) PaidLeaveBalance(staffID int, asOf string) (*domain.PaidLeaveBalance, error) {
+	balances, err := u.PaidLeaveBalances(asOf)
+	if err != nil {
+		return nil, err
+	}
+	for _, b := range balances {
+		if b.StaffID == staffID {
+			return &b, nil
+		}
+	}
+	return nil, domain.ErrNotFound
+}
+
+// checkPaidLeaveBalance: date に有給を取れるだけの残日数があるか
+// 承認待ちの申請も取得したものとして数える
+func (u *ShiftUsecase) checkPaidLeaveBalance(staff domain.Staff, date string) error {
+	requests, err := u.requestRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	return paidLeaveFits(staff, paidLeaveBooked(requests, int(staff.ID)), date)
+}
+
+// paidLeaveBooked: 承認済み・承認待ちの有給の日付（日付の前後を問わない）
+func paidLeaveBooked(requests []domain.ShiftRequest, staffID int) []string {
+	var booked []string
+	for _, r := range requests {
+		if r.StaffID == staffID && r.Type == domain.RequestTypePaidLeave &&
+			(r.Status == domain.RequestApproved || r.Status == domain.RequestPending) {
+			booked = append(booked, r.Date)
+		}
+	}
+	return booked
+}
+
+// paidLeaveFits: 申請済みの booked に date を足しても付与日数に収まるか
+// 古い付与から順に消化するので、後の日付の申請が押し出されて付与外になる場合も残日数不足とする
+func paidLeaveFits(staff domain.Staff, booked []string, date string) error {
+	asOf := date
+	for _, d := range booked {
+		if d > asOf {
+			asOf = d
+		}
+	}
+	before, err := paidLeaveBalance(staff, append([]string{}, booked...), 0, asOf)
+	if err != nil {
+		return err
+	}
+	after, err := paidLeaveBalance(staff, append(append([]string{}, booked...), date), 0, asOf)
+	if err != nil {
+		return err
+	}
+	if after.Unallocated > before.Unallocated {
+		return fmt.Errorf("%w: %sに使える有給の残日数がありません", domain.ErrInvalidInput, date)
+	}
+	return nil
+}
+
+// paidLeaveDates: 承認済みの有給の日付（asOf まで）と、承認待ちの日数
+func paidLeaveDates(requests []domain.ShiftRequest, staffID int, asOf string) ([]string, int) {
+	var taken []string
+	pending := 0
+	for _, r := range requests {
+		if r.StaffID != staffID || r.Type != domain.RequestTypePaidLeave {
+			continue
+		}
+		switch r.Status {
+		case domain.RequestApproved:
+			if r.Date <= asOf {
+				taken = append(taken, r.Date)
+			}
+		case domain.RequestPending:
+			pending++
+		}
+	}
+	return taken, pending
+}
+
+// addYears: "2026-02-01" の n 年後
+func addYears(date string, n int) string {
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return date
+	}
+	return t.AddDate(n, 0, 0).Format(dateLayout)
+}
@@ -0,0 +1,175 @@
+package usecase
+
+import (
+	"errors"
+	"smart-shift-scheduler/internal/domain"
+	"testing"
+)
+
+func TestPaidLeaveEntitlement(t *testing.T) {
+	tests := []struct {
+		name  string
+		days  int
+		hours float64
+		n     int
+		want  int
+	}{
+		{"フルタイム 初回", 0, 0, 0, 10},
+		{"フルタイム 2回目", 5, 40, 1, 11},
+		{"フルタイム 7回目以降は20日", 5, 40, 6, 20},
+		{"フルタイム 上限を超えた回数", 5, 40, 10, 20},
+		{"週4日 30時間未満は比例付与", 4, 20, 0, 7},
+		{"週4日 30時間未満 7回目", 4, 20, 6, 15},
+		{"週4日でも30時間以上ならフルタイム", 4, 30, 0, 10},
+		{"週3日 3回目", 3, 18, 2, 6},
+		{"週1日 初回", 1, 4, 0, 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			staff := domain.Staff{WeeklyScheduledDays: tt.days, WeeklyScheduledHours: tt.hours}
+			if got := paidLeaveEntitlement(staff, tt.n); got != tt.want {
+				t.Errorf("paidLeaveEntitlement(%d日/%v時間, %d) = %d, want %d", tt.days, tt.hours, tt.n, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPaidLeaveBalance(t *testing.T) {
+	tests := []struct {
+		name        string
+		staff       domain.Staff
+		taken       []string
+		asOf        string
+		grants      int
+		remaining   int
+		expiring    int
+		nextExpiry  string
+		unallocated int
+		obligations []string // 付与日ごとの取得義務の状態
+	}{
+		{
+			name:        "入社日が未登録なら取得日はすべて付与外",
+			staff:       domain.Staff{},
+			taken:       []string{"2025-01-10", "2025-01-11"},
+			asOf:        "2025-03-31",
+			unallocated: 2,
+		},
+		{
+			name:  "入社6か月前は付与なし",
+			staff: domain.Staff{HireDate: "2024-04-01"},
+			asOf:  "2024-09-30",
+		},
+		{
+			name:        "初回付与から取得した分を引く",
+			staff:       domain.Staff{HireDate: "2024-04-01"},
+			taken:       []string{"2025-01-10", "2024-12-01"},
+			asOf:        "2025-03-31",
+			grants:      1,
+			remaining:   8,
+			nextExpiry:  "2026-09-30",
+			obligations: []string{domain.ObligationAtRisk},
+		},
+		{
+			name:        "2年で失効し、90日以内に失効する分を数える",
+			staff:       domain.Staff{HireDate: "2020-04-01"},
+			asOf:        "2023-08-01",
+			grants:      3,
+			remaining:   23,
+			expiring:    11,
+			nextExpiry:  "2023-09-30",
+			obligations: []string{domain.ObligationMissed, domain.ObligationMissed, domain.ObligationAtRisk},
+		},
+		{
+			name:        "付与前の取得と付与日数を超えた取得は付与外",
+			staff:       domain.Staff{HireDate: "2024-04-01"},
+			taken:       append([]string{"2024-09-01"}, datesFrom("2024-10-01", 11)...),
+			asOf:        "2024-12-31",
+			grants:      1,
+			unallocated: 2,
+			obligations: []string{domain.ObligationMet},
+		},
+		{
+			name:       "10日未満の付与には取得義務が無い",
+			staff:      domain.Staff{HireDate: "2024-04-01", WeeklyScheduledDays: 3, WeeklyScheduledHours: 18},
+			asOf:       "2024-12-31",
+			grants:     1,
+			remaining:  5,
+			nextExpiry: "2026-09-30",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := paidLeaveBalance(tt.staff, tt.taken, 0, tt.asOf)
+			if err != nil {
+				t.Fatalf("paidLeaveBalance: %v", err)
+			}
+			if len(got.Grants) != tt.grants {
+				t.Errorf("grants = %d, want %d", len(got.Grants), tt.grants)
+			}
+			if got.Remaining != tt.remaining {
+				t.Errorf("remaining = %d, want %d", got.Remaining, tt.remaining)
+			}
+			if got.Expiring != tt.expiring {
+				t.Errorf("expiring = %d, want %d", got.Expiring, tt.expiring)
+			}
+			if got.NextExpiry != tt.nextExpiry {
+				t.Errorf("next_expiry = %q, want %q", got.NextExpiry, tt.nextExpiry)
+			}
+			if got.Unallocated != tt.unallocated {
+				t.Errorf("unallocated = %d, want %d", got.Unallocated, tt.unallocated)
+			}
+			var statuses []string
+			for _, ob := range got.Obligations {
+				statuses = append(statuses, ob.Status)
+			}
+			if !equalStrings(statuses, tt.obligations) {
+				t.Errorf("obligations = %v, want %v", statuses, tt.obligations)
+			}
+		})
+	}
+}
+
+func TestPaidLeaveBalanceInvalidHireDate(t *testing.T) {
+	_, err := paidLeaveBalance(domain.Staff{HireDate: "2024/04/01"}, nil, 0, "2025-01-01")
+	if !errors.Is(err, domain.ErrInvalidInput) {
+		t.Errorf("err = %v, want ErrInvalidInput", err)
+	}
+}
+
+// datesFrom: start から n 日分の日付
+func datesFrom(start string, n int) []string {
+	var dates []string
+	for i := 0; i < n; i++ {
+		dates = append(dates, addDays(start, i))
+	}
+	return dates
+}
+
+func TestPaidLeaveFits(t *testing.T) {
+	// 2024-10-01 に10日付与
+	hired := domain.Staff{HireDate: "2024-04-01"}
+	tests := []struct {
+		name   string
+		staff  domain.Staff
+		booked []string
+		date   string
+		ok     bool
+	}{
+		{"残日数の範囲内", hired, datesFrom("2024-11-01", 9), "2024-12-01", true},
+		{"付与日数を使い切っている", hired, datesFrom("2024-11-01", 10), "2024-12-01", false},
+		{"後の日付の申請も数える", hired, datesFrom("2025-03-01", 10), "2024-12-01", false},
+		{"付与前の日付", hired, nil, "2024-09-30", false},
+		{"入社日が未登録", domain.Staff{}, nil, "2024-12-01", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := paidLeaveFits(tt.staff, tt.booked, tt.date)
+			if tt.ok && err != nil {
+				t.Errorf("paidLeaveFits = %v, want nil", err)
+			}
+			if !tt.ok && !errors.Is(err, domain.ErrInvalidInput) {
+				t.Errorf("paidLeaveFits = %v, want ErrInvalidInput", err)
+			}
+		})
+	}
+}
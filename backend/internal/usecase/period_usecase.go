@@ -0,0 +1,146 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"time"
+)
+
+type PeriodRepository interface {
+	Save(period *domain.SchedulePeriod) error
+	FindAll() ([]domain.SchedulePeriod, error)
+	FindByID(id int) (*domain.SchedulePeriod, error)
+	FindCovering(date string) (*domain.SchedulePeriod, error)
+	FindOverlapping(startDate string, endDate string) ([]domain.SchedulePeriod, error)
+	UpdateStatus(id uint, status string) error
+	Publish(period *domain.SchedulePeriod, shifts []domain.Shift) error
+	FindPublishedShifts(periodID uint) ([]domain.PublishedShift, error)
+	Delete(id int) error
+}
+
+// PeriodUsecase: シフト期間の作成と 下書き → 公開 → 確定 の管理
+type PeriodUsecase struct {
+	periodRepo  PeriodRepository
+	shiftRepo   ShiftRepository
+	versionRepo VersionRepository
+	staffRepo   domain.StaffRepository
+}
+
+func NewPeriodUsecase(periodRepo PeriodRepository, shiftRepo ShiftRepository, versionRepo VersionRepository, staffRepo domain.StaffRepository) *PeriodUsecase {
+	return &PeriodUsecase{periodRepo: periodRepo, shiftRepo: shiftRepo, versionRepo: versionRepo, staffRepo: staffRepo}
+}
+
+// CreatePeriod: 期間を作成（下書き状態で始まる）
+func (u *PeriodUsecase) CreatePeriod(period *domain.SchedulePeriod) error {
+	start, err := time.Parse("2006-01-02", period.StartDate)
+	if err != nil {
+		return fmt.Errorf("%w: 日付形式エラー: %v", domain.ErrInvalidInput, err)
+	}
+	end, err := time.Parse("2006-01-02", period.EndDate)
+	if err != nil {
+		return fmt.Errorf("%w: 日付形式エラー: %v", domain.ErrInvalidInput, err)
+	}
+	if end.Before(start) {
+		return fmt.Errorf("%w: 終了日は開始日以降にしてください", domain.ErrInvalidInput)
+	}
+	if period.RequestDeadlineDays < 0 {
+		return fmt.Errorf("%w: request_deadline_days は0以上にしてください", domain.ErrInvalidInput)
+	}
+
+	overlapping, err := u.periodRepo.FindOverlapping(period.StartDate, period.EndDate)
+	if err != nil {
+		return err
+	}
+	if len(overlapping) > 0 {
+		return domain.ErrPeriodOverlap
+	}
+
+	period.ID = 0
+	period.Status = domain.PeriodDraft
+	period.PublishedAt = nil
+	return u.periodRepo.Save(period)
+}
+
+func (u *PeriodUsecase) ListPeriods() ([]domain.SchedulePeriod, error) {
+	return u.periodRepo.FindAll()
+}
+
+func (u *PeriodUsecase) GetPeriod(id int) (*domain.SchedulePeriod, error) {
+	return u.periodRepo.FindByID(id)
+}
+
+// DeletePeriod: 期間を削除（確定済みは不可）
+func (u *PeriodUsecase) DeletePeriod(id int) error {
+	period, err := u.periodRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if period.Status == domain.PeriodLocked {
+		return domain.ErrPeriodLocked
+	}
+	return u.periodRepo.Delete(id)
+}
+
+// Publish: 今のシフトを公開する（スタッフが見る内容はこの時点で固定される）
+// 下書き・公開済みのどちらからでも再公開できる
+func (u *PeriodUsecase) Publish(id int) (*domain.SchedulePeriod, error) {
+	period, err := u.periodRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if period.Status == domain.PeriodLocked {
+		return nil, domain.ErrPeriodLocked
+	}
+
+	shifts, err := u.shiftRepo.FindRange(period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.periodRepo.Publish(period, shifts); err != nil {
+		return nil, err
+	}
+	return period, nil
+}
+
+// Lock: 公開済みの期間を確定する（以降は編集・削除・再生成ができない）
+func (u *PeriodUsecase) Lock(id int) (*domain.SchedulePeriod, error) {
+	return u.changeStatus(id, domain.PeriodLocked, domain.PeriodPublished)
+}
+
+// Reopen: 公開済み・確定済みの期間を下書きに戻す（公開中の内容は再公開まで残る）
+func (u *PeriodUsecase) Reopen(id int) (*domain.SchedulePeriod, error) {
+	return u.changeStatus(id, domain.PeriodDraft, domain.PeriodPublished, domain.PeriodLocked)
+}
+
+// changeStatus: from のいずれかのステータスのときだけ to に変更する
+func (u *PeriodUsecase) changeStatus(id int, to string, from ...string) (*domain.SchedulePeriod, error) {
+	period, err := u.periodRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+
+	allowed := false
+	for _, f := range from {
+		if period.Status == f {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, domain.ErrInvalidPeriodStatus
+	}
+
+	if err := u.periodRepo.UpdateStatus(period.ID, to); err != nil {
+		return nil, err
+	}
+	period.Status = to
+	return period, nil
+}
+
+// ListPublishedShifts: スタッフ向けの公開済みシフト
+func (u *PeriodUsecase) ListPublishedShifts(id int) ([]domain.PublishedShift, error) {
+	period, err := u.periodRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	return u.periodRepo.FindPublishedShifts(period.ID)
+}
@@ -0,0 +1,273 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+type CallOutRepository interface {
+	Find(from string, to string) ([]domain.CallOut, error)
+	Reassign(shift *domain.Shift, callOut *domain.CallOut) error
+}
+
+// ReplacementUsecase: 当日欠勤の代わりの人探しと付け替え
+type ReplacementUsecase struct {
+	callOutRepo CallOutRepository
+	shifts      *ShiftUsecase // ルール判定・確定済み期間のチェックに使う
+}
+
+func NewReplacementUsecase(callOutRepo CallOutRepository, shifts *ShiftUsecase) *ReplacementUsecase {
+	return &ReplacementUsecase{callOutRepo: callOutRepo, shifts: shifts}
+}
+
+// Reassignment: 付け替えた結果（監査ログ用に付け替え前後のシフトも返す）
+type Reassignment struct {
+	CallOut    *domain.CallOut
+	Before     domain.Shift
+	After      domain.Shift
+	Violations []domain.Violation // 警告（必須ルールの違反があれば付け替えない）
+}
+
+// FindReplacements: シフトの代わりに入れる人を、入れる人 → スコアの小さい順に並べる
+func (u *ReplacementUsecase) FindReplacements(shiftID int) (*domain.Replacements, error) {
+	shift, err := u.shifts.shiftRepo.FindByID(shiftID)
+	if err != nil {
+		return nil, domain.ErrNotFound
+	}
+	return u.replacements(shift)
+}
+
+// Reassign: シフトを staffID に付け替えて、欠勤を記録する
+// 候補の判定で入れない人（必須ルール違反・上限超え）には付け替えない
+func (u *ReplacementUsecase) Reassign(shiftID int, staffID int, reason string, actor string) (*Reassignment, error) {
+	shift, err := u.shifts.shiftRepo.FindByID(shiftID)
+	if err != nil {
+		return nil, domain.ErrNotFound
+	}
+	if err := u.shifts.checkDateNotLocked(shift.Date); err != nil {
+		return nil, err
+	}
+	if staffID == shift.StaffID {
+		return nil, fmt.Errorf("%w: 欠勤する本人には付け替えられません", domain.ErrInvalidInput)
+	}
+
+	result, err := u.replacements(shift)
+	if err != nil {
+		return nil, err
+	}
+	var candidate *domain.ReplacementCandidate
+	for i := range result.Candidates {
+		if result.Candidates[i].StaffID == staffID {
+			candidate = &result.Candidates[i]
+		}
+	}
+	if candidate == nil {
+		return nil, fmt.Errorf("%w: スタッフが見つかりません", domain.ErrInvalidInput)
+	}
+	if !candidate.Eligible {
+		return &Reassignment{Violations: candidate.Violations},
+			fmt.Errorf("%w: %s", domain.ErrRuleViolation, strings.Join(candidate.Reasons, "、"))
+	}
+
+	before := *shift
+	callOut := &domain.CallOut{
+		ShiftID:       shift.ID,
+		Date:          shift.Date,
+		ShiftType:     shift.ShiftType,
+		AbsentStaffID: shift.StaffID,
+		ReplacementID: staffID,
+		Reason:        reason,
+		Actor:         actor,
+	}
+	if err := u.callOutRepo.Reassign(shift, callOut); err != nil {
+		return nil, err
+	}
+	if err := u.shifts.snapshotEdit("欠勤による付け替え", shift.Date); err != nil {
+		return nil, err
+	}
+	return &Reassignment{CallOut: callOut, Before: before, After: *shift, Violations: candidate.Violations}, nil
+}
+
+// ListCallOuts: 欠勤の記録の一覧
+func (u *ReplacementUsecase) ListCallOuts(from string, to string) ([]domain.CallOut, error) {
+	return u.callOutRepo.Find(from, to)
+}
+
+// replacements: 欠勤する本人以外の全スタッフを判定する
+func (u *ReplacementUsecase) replacements(shift *domain.Shift) (*domain.Replacements, error) {
+	t, ok := domain.FindShiftTemplate(shift.ShiftType)
+	if !ok {
+		return nil, fmt.Errorf("%w: 休みのシフトには代わりの人を探せません", domain.ErrInvalidInput)
+	}
+	date, err := time.Parse(dateLayout, shift.Date)
+	if err != nil {
+		return nil, fmt.Errorf("%w: 日付形式エラー: %v", domain.ErrInvalidInput, err)
+	}
+	rc, err := u.shifts.loadRuleContext()
+	if err != nil {
+		return nil, err
+	}
+
+	// 年収の上限を見るため、その年の全シフトを読み込む（年をまたぐ前後2週間も含める）
+	year := date.Year()
+	from, to := fmt.Sprintf("%04d-01-01", year), fmt.Sprintf("%04d-12-31", year)
+	if d := addDays(shift.Date, -14); d < from {
+		from = d
+	}
+	if d := addDays(shift.Date, 14); d > to {
+		to = d
+	}
+	all, err := u.shifts.shiftRepo.FindRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	var window []domain.Shift
+	for _, s := range all {
+		if s.Date >= addDays(shift.Date, -14) && s.Date <= addDays(shift.Date, 14) {
+			window = append(window, s)
+		}
+	}
+
+	result := &domain.Replacements{Shift: *shift, RequiredRoles: rc.requiredRoles(window, shift), Candidates: []domain.ReplacementCandidate{}}
+	var ids []int
+	for id := range rc.staff {
+		if id != shift.StaffID {
+			ids = append(ids, id)
+		}
+	}
+	sort.Ints(ids)
+	for _, id := range ids {
+		c := rc.replacementCandidate(shift, t, id, window, all, result.RequiredRoles)
+		result.Candidates = append(result.Candidates, c)
+	}
+	rankReplacements(result.Candidates)
+	return result, nil
+}
+
+// requiredRoles: shift が抜けると人数が足りなくなる役割
+func (rc *ruleContext) requiredRoles(shifts []domain.Shift, absent *domain.Shift) []string {
+	roles := []string{}
+	for _, role := range rc.roles {
+		got := 0
+		for _, s := range shifts {
+			if s.ID != absent.ID && s.Date == absent.Date && s.ShiftType != 0 && rc.hasRole(s.StaffID, role.Role) {
+				got++
+			}
+		}
+		if got < role.Count {
+			roles = append(roles, role.Role)
+		}
+	}
+	return roles
+}
+
+// replacementCandidate: staffID が shift の代わりに入れるか
+// window は前後2週間、year は年間のシフト
+func (rc *ruleContext) replacementCandidate(shift *domain.Shift, t domain.ShiftTemplate, staffID int, window []domain.Shift, year []domain.Shift, roles []string) domain.ReplacementCandidate {
+	staff := rc.staff[staffID]
+	hours := t.Hours()
+	c := domain.ReplacementCandidate{
+		StaffID:    staffID,
+		Name:       staff.Name,
+		Roles:      staff.Roles,
+		Eligible:   true,
+		Reasons:    []string{},
+		Violations: []domain.Violation{},
+		Cost:       int(hours * float64(staff.HourlyWage)),
+	}
+
+	// 1. 出勤状況・前後2週間の出勤日数・その週の労働時間
+	working := false
+	weekStart := weekStarts(shift.Date, shift.Date)[0]
+	c.WeekHours = hours
+	for _, s := range window {
+		st, ok := domain.FindShiftTemplate(s.ShiftType)
+		if s.StaffID != staffID || !ok {
+			continue
+		}
+		c.WorkDays++
+		if s.Date == shift.Date {
+			working = true
+		}
+		if s.Date >= weekStart && s.Date <= addDays(weekStart, 6) {
+			c.WeekHours += st.Hours()
+		}
+	}
+	c.YearIncome = c.Cost
+	for _, s := range year {
+		if st, ok := domain.FindShiftTemplate(s.ShiftType); ok && s.StaffID == staffID && s.Date[:4] == shift.Date[:4] {
+			c.YearIncome += int(st.Hours() * float64(staff.HourlyWage))
+		}
+	}
+
+	if working {
+		c.Reasons = append(c.Reasons, "この日はすでに出勤しています")
+	}
+	if rc.requests[cellKey(staffID, shift.Date)] {
+		c.Reasons = append(c.Reasons, "この日に休み希望（有給を含む）が出ています")
+	}
+	for _, role := range roles {
+		if !rc.hasRole(staffID, role) {
+			c.Reasons = append(c.Reasons, fmt.Sprintf("%sの役割がありません", role))
+		}
+	}
+
+	// 2. 付け替えた場合のルール違反（上で理由にしたものは重ねない）
+	replaced := *shift
+	replaced.StaffID = staffID
+	for _, v := range rc.changeViolations(window, []domain.Shift{*shift}, []domain.Shift{replaced}) {
+		c.Violations = append(c.Violations, v)
+		switch v.Rule {
+		case "double_booking", "ng_request", "role_minimum":
+			continue
+		}
+		if v.Level == domain.ViolationHard {
+			c.Reasons = append(c.Reasons, v.Message)
+		}
+	}
+
+	// 3. 労働時間・年収の上限
+	if staff.MaxWeeklyHours > 0 && c.WeekHours > staff.MaxWeeklyHours {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("週の労働時間が%.1f時間になります（上限%.1f時間）", c.WeekHours, staff.MaxWeeklyHours))
+	}
+	if staff.AnnualIncomeCap > 0 && c.YearIncome > staff.AnnualIncomeCap {
+		c.Reasons = append(c.Reasons, fmt.Sprintf("%s年の見込み収入が%d円になります（上限%d円）", shift.Date[:4], c.YearIncome, staff.AnnualIncomeCap))
+	}
+
+	c.Eligible = len(c.Reasons) == 0
+	return c
+}
+
+// rankReplacements: 人件費と前後2週間の出勤日数から Score を計算して並べる
+// 入れる人が先、同じスコアなら警告が少ない人 → ID順
+func rankReplacements(candidates []domain.ReplacementCandidate) {
+	maxCost, maxDays := 0, 0
+	for _, c := range candidates {
+		if c.Cost > maxCost {
+			maxCost = c.Cost
+		}
+		if c.WorkDays > maxDays {
+			maxDays = c.WorkDays
+		}
+	}
+	for i := range candidates {
+		c := &candidates[i]
+		c.Score = 0.5*ratio(c.Cost, maxCost) + 0.5*ratio(c.WorkDays, maxDays)
+	}
+	sort.SliceStable(candidates, func(i, j int) bool {
+		a, b := candidates[i], candidates[j]
+		if a.Eligible != b.Eligible {
+			return a.Eligible
+		}
+		if a.Score != b.Score {
+			return a.Score < b.Score
+		}
+		if len(a.Violations) != len(b.Violations) {
+			return len(a.Violations) < len(b.Violations)
+		}
+		return a.StaffID < b.StaffID
+	})
+}
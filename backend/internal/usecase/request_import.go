@@ -0,0 +1,281 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"strings"
+	"time"
+)
+
+// 取り込みファイルの列の見出し（英語のキーか日本語の別名）
+var (
+	importStaffIDColumn = []string{"staff_id", "スタッフID"}
+	importCodeColumn    = []string{"employee_code", "社員番号", "従業員番号", "code"}
+	importNameColumn    = []string{"name", "staff_name", "名前", "氏名", "スタッフ名"}
+	importDateColumn    = []string{"date", "日付"}
+	importTypeColumn    = []string{"type", "種類", "区分"}
+	importNoteColumn    = []string{"note", "理由", "備考", "メモ"}
+	importMorningColumn = []string{"morning_need", "早番", "早番人数"}
+	importEveningColumn = []string{"evening_need", "遅番", "遅番人数"}
+)
+
+// ImportRequests: 休み希望（紙の申請書・スプレッドシート）を取り込む
+// status は取り込んだ希望の状態（pending = 承認待ち、approved = 店長が確認済みとして承認する）
+// 店長が取りまとめた分なので、締切日と月の上限では止めない（公開済み・確定済みの期間には入れない）
+// 有給は法定の残日数を超えられないので、ファイル内の前の行も含めてスタッフごとに確認する
+// エラーか食い違いが1件でもあれば何も保存しない。dryRun なら確認だけ
+func (u *ShiftUsecase) ImportRequests(table *domain.ImportTable, status string, actor string, dryRun bool) (*domain.RequestImportResult, error) {
+	if status == "" {
+		status = domain.RequestPending
+	}
+	if status != domain.RequestPending && status != domain.RequestApproved {
+		return nil, fmt.Errorf("%w: status は %s か %s を指定してください", domain.ErrInvalidInput, domain.RequestPending, domain.RequestApproved)
+	}
+	idCol, codeCol, nameCol := table.Column(importStaffIDColumn...), table.Column(importCodeColumn...), table.Column(importNameColumn...)
+	dateCol, typeCol, noteCol := table.Column(importDateColumn...), table.Column(importTypeColumn...), table.Column(importNoteColumn...)
+	if dateCol < 0 {
+		return nil, fmt.Errorf("%w: date 列が必要です", domain.ErrInvalidInput)
+	}
+	if idCol < 0 && codeCol < 0 && nameCol < 0 {
+		return nil, fmt.Errorf("%w: staff_id, employee_code, name のいずれかの列が必要です", domain.ErrInvalidInput)
+	}
+
+	staffList, err := u.staffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	resolver := newStaffResolver(staffList)
+	existing, err := u.requestRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	submitted := make(map[string]domain.ShiftRequest) // スタッフ×日付 -> 提出済みの希望（却下は除く）
+	for _, r := range existing {
+		if r.Status != domain.RequestRejected {
+			submitted[requestKey(r.StaffID, r.Date)] = r
+		}
+	}
+	periods := make(map[string]*domain.SchedulePeriod) // 日付 -> その日を含む期間
+	booked := make(map[int][]string)                   // スタッフ -> 申請済みの有給の日付（取り込む行を含む）
+
+	result := &domain.RequestImportResult{ImportSummary: domain.ImportSummary{DryRun: dryRun}}
+	var errs, duplicates, conflicts importErrors
+	seen := make(map[string]int) // スタッフ×日付 -> 最初に出てきた行
+	now := time.Now()
+	for _, row := range table.Rows {
+		staff, err := resolver.resolve(row.Value(idCol), row.Value(codeCol), row.Value(nameCol))
+		if err != nil {
+			errs.add(row.Line, "staff", err.Error())
+			continue
+		}
+		date, err := parseImportDate(row.Value(dateCol))
+		if err != nil {
+			errs.add(row.Line, table.Header[dateCol], err.Error())
+			continue
+		}
+		reqType, err := parseRequestType(row.Value(typeCol))
+		if err != nil {
+			errs.add(row.Line, table.Header[typeCol], err.Error())
+			continue
+		}
+
+		key := requestKey(int(staff.ID), date)
+		if first, dup := seen[key]; dup {
+			duplicates.add(row.Line, "", fmt.Sprintf("%s の %s は %d 行目と同じです", staff.Name, date, first))
+			result.Skipped++
+			continue
+		}
+		seen[key] = row.Line
+		if prev, ok := submitted[key]; ok {
+			if prev.Type == reqType {
+				duplicates.add(row.Line, "", fmt.Sprintf("%s の %s はすでに申請されています（%s）", staff.Name, date, prev.Status))
+				result.Skipped++
+			} else {
+				conflicts.add(row.Line, "", fmt.Sprintf("%s の %s はすでに %s で申請されています（%s）", staff.Name, date, prev.Type, prev.Status))
+			}
+			continue
+		}
+
+		period, ok := periods[date]
+		if !ok {
+			if period, err = u.periodRepo.FindCovering(date); err != nil {
+				return nil, err
+			}
+			periods[date] = period
+		}
+		if period != nil && period.Status != domain.PeriodDraft {
+			conflicts.add(row.Line, table.Header[dateCol], fmt.Sprintf("%s は %s の期間に含まれています", date, period.Status))
+			continue
+		}
+		if reqType == domain.RequestTypePaidLeave {
+			staffID := int(staff.ID)
+			if _, ok := booked[staffID]; !ok {
+				booked[staffID] = paidLeaveBooked(existing, staffID)
+			}
+			if err := paidLeaveFits(staff, booked[staffID], date); err != nil {
+				errs.add(row.Line, table.Header[typeCol], fmt.Sprintf("%s: %v", staff.Name, err))
+				continue
+			}
+			booked[staffID] = append(booked[staffID], date)
+		}
+
+		req := domain.ShiftRequest{StaffID: int(staff.ID), Date: date, Type: reqType, Note: row.Value(noteCol), Status: status}
+		if status == domain.RequestApproved {
+			req.ReviewedBy = actor
+			req.ReviewedAt = &now
+		}
+		result.Rows = append(result.Rows, domain.RequestImportRow{Line: row.Line, Action: domain.ImportActionCreate, Request: req})
+		result.Created++
+	}
+	result.Errors, result.Duplicates, result.Conflicts = errs, duplicates, conflicts
+
+	if result.Blocked() {
+		if dryRun {
+			return result, nil
+		}
+		return result, fmt.Errorf("%w: エラーが %d 件、競合が %d 件あるため、取り込みませんでした", domain.ErrInvalidInput, len(errs), len(conflicts))
+	}
+	if dryRun || len(result.Rows) == 0 {
+		return result, nil
+	}
+
+	requests := make([]domain.ShiftRequest, len(result.Rows))
+	for i, row := range result.Rows {
+		requests[i] = row.Request
+	}
+	if err := u.requestRepo.SaveAll(requests); err != nil {
+		return nil, err
+	}
+	for i := range result.Rows {
+		result.Rows[i].Request = requests[i]
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// ImportRequirements: 日ごとの必要人数（エリアマネージャーの表）を取り込む
+// 設定済みの日と人数が違うときは食い違いとして止める（overwrite なら上書きする）
+// エラーか食い違いが1件でもあれば何も保存しない。dryRun なら確認だけ
+func (u *ShiftUsecase) ImportRequirements(table *domain.ImportTable, overwrite bool, dryRun bool) (*domain.RequirementImportResult, error) {
+	dateCol, morningCol, eveningCol := table.Column(importDateColumn...), table.Column(importMorningColumn...), table.Column(importEveningColumn...)
+	if dateCol < 0 || (morningCol < 0 && eveningCol < 0) {
+		return nil, fmt.Errorf("%w: date 列と morning_need / evening_need 列が必要です", domain.ErrInvalidInput)
+	}
+
+	existing, err := u.requireRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	byDate := make(map[string]domain.DailyRequirement)
+	for _, r := range existing {
+		byDate[r.Date] = r
+	}
+
+	result := &domain.RequirementImportResult{ImportSummary: domain.ImportSummary{DryRun: dryRun}}
+	var errs, duplicates, conflicts importErrors
+	seen := make(map[string]domain.RequirementImportRow) // 日付 -> 最初に出てきた行
+	for _, row := range table.Rows {
+		date, err := parseImportDate(row.Value(dateCol))
+		if err != nil {
+			errs.add(row.Line, table.Header[dateCol], err.Error())
+			continue
+		}
+		req := domain.DailyRequirement{Date: date}
+		valid := true
+		for _, f := range []struct {
+			col    int
+			target *int
+		}{{morningCol, &req.MorningNeed}, {eveningCol, &req.EveningNeed}} {
+			v := row.Value(f.col)
+			if v == "" {
+				continue // 空欄は0人
+			}
+			n, err := parseImportInt(v)
+			if err != nil {
+				errs.add(row.Line, table.Header[f.col], err.Error())
+				valid = false
+			}
+			*f.target = n
+		}
+		if !valid {
+			continue
+		}
+
+		if first, dup := seen[date]; dup {
+			if sameNeeds(first.Requirement, req) {
+				duplicates.add(row.Line, "", fmt.Sprintf("%s は %d 行目と同じです", date, first.Line))
+				result.Skipped++
+			} else {
+				conflicts.add(row.Line, "", fmt.Sprintf("%s は %d 行目と人数が違います", date, first.Line))
+			}
+			continue
+		}
+		entry := domain.RequirementImportRow{Line: row.Line, Action: domain.ImportActionCreate, Requirement: req}
+		seen[date] = entry
+
+		if prev, ok := byDate[date]; ok {
+			if sameNeeds(prev, req) {
+				duplicates.add(row.Line, "", fmt.Sprintf("%s はすでに同じ人数で登録されています", date))
+				result.Skipped++
+				continue
+			}
+			if !overwrite {
+				conflicts.add(row.Line, "", fmt.Sprintf("%s はすでに早番 %d 人 / 遅番 %d 人で登録されています（置き換えるなら overwrite=true）", date, prev.MorningNeed, prev.EveningNeed))
+				continue
+			}
+			entry.Action = domain.ImportActionUpdate
+			entry.Before = &prev
+			entry.Requirement.ID = prev.ID
+		}
+
+		if entry.Action == domain.ImportActionCreate {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		result.Rows = append(result.Rows, entry)
+	}
+	result.Errors, result.Duplicates, result.Conflicts = errs, duplicates, conflicts
+
+	if result.Blocked() {
+		if dryRun {
+			return result, nil
+		}
+		return result, fmt.Errorf("%w: エラーが %d 件、競合が %d 件あるため、取り込みませんでした", domain.ErrInvalidInput, len(errs), len(conflicts))
+	}
+	if dryRun || len(result.Rows) == 0 {
+		return result, nil
+	}
+
+	reqs := make([]domain.DailyRequirement, len(result.Rows))
+	for i, row := range result.Rows {
+		reqs[i] = row.Requirement
+	}
+	if err := u.requireRepo.SaveAll(reqs); err != nil {
+		return nil, err
+	}
+	for i := range result.Rows {
+		result.Rows[i].Requirement = reqs[i]
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// parseRequestType: NG / PAID（空なら NG）。日本語の書き方も受け付ける
+func parseRequestType(v string) (string, error) {
+	switch strings.ToUpper(v) {
+	case "", domain.RequestTypeNG, "休み", "休み希望", "希望休", "公休":
+		return domain.RequestTypeNG, nil
+	case domain.RequestTypePaidLeave, "有給", "有休", "有給休暇":
+		return domain.RequestTypePaidLeave, nil
+	}
+	return "", fmt.Errorf("%s か %s を指定してください", domain.RequestTypeNG, domain.RequestTypePaidLeave)
+}
+
+func requestKey(staffID int, date string) string {
+	return fmt.Sprintf("%d|%s", staffID, date)
+}
+
+func sameNeeds(a domain.DailyRequirement, b domain.DailyRequirement) bool {
+	return a.MorningNeed == b.MorningNeed && a.EveningNeed == b.EveningNeed
+}
@@ -0,0 +1,97 @@
+package usecase
+
+import (
+	"math"
+	"smart-shift-scheduler/internal/domain"
+	"time"
+)
+
+// ScheduleGrid: from〜to のシフトをスタッフ×日付の表にする（日ごとの人数、人ごとの時間・人件費つき）
+func (u *ShiftUsecase) ScheduleGrid(from string, to string) (*domain.ScheduleGrid, error) {
+	if err := checkDateRange(from, to); err != nil {
+		return nil, err
+	}
+	shifts, err := u.shiftRepo.FindRange(from, to)
+	if err != nil {
+		return nil, err
+	}
+	rc, err := u.loadRuleContext()
+	if err != nil {
+		return nil, err
+	}
+	staffList, err := u.staffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	requests, err := u.approvedRequests()
+	if err != nil {
+		return nil, err
+	}
+
+	grid := &domain.ScheduleGrid{From: from, To: to, Templates: domain.ShiftTemplates}
+	if period, err := u.periodRepo.FindCovering(from); err == nil && period != nil {
+		grid.StoreName = period.StoreName
+	}
+
+	holidays := domain.Holidays(from, to)
+	index := make(map[string]int)
+	for date := from; date <= to; date = addDays(date, 1) {
+		t, _ := time.Parse(dateLayout, date)
+		day := domain.GridDay{Date: date, Weekday: int(t.Weekday()), Holiday: holidays[date], Counts: map[int]int{}, Required: map[int]int{}}
+		for _, tmpl := range domain.ShiftTemplates {
+			day.Counts[tmpl.Type] = 0
+			day.Required[tmpl.Type] = rc.need(date, tmpl.Type)
+		}
+		index[date] = len(grid.Days)
+		grid.Days = append(grid.Days, day)
+	}
+
+	rows := make(map[int]*domain.GridRow)
+	costs := make(map[int]float64)
+	for _, st := range staffList {
+		rows[int(st.ID)] = &domain.GridRow{
+			StaffID: int(st.ID),
+			Name:    st.Name,
+			Cells:   make([]int, len(grid.Days)),
+			Marks:   make([]string, len(grid.Days)),
+		}
+	}
+
+	for _, s := range shifts {
+		row, ok := rows[s.StaffID]
+		t, isWork := domain.FindShiftTemplate(s.ShiftType)
+		if !ok || !isWork {
+			continue
+		}
+		i := index[s.Date]
+		if row.Cells[i] != 0 {
+			continue // 二重登録は1件だけ数える
+		}
+		row.Cells[i] = s.ShiftType
+		row.WorkDays++
+		row.Hours += t.Hours()
+		costs[s.StaffID] += t.Hours() * float64(rc.staff[s.StaffID].HourlyWage)
+		grid.Days[i].Counts[s.ShiftType]++
+		grid.Days[i].Total++
+	}
+	// 有給は所定労働時間分を人件費に含める（Evaluate と同じ）
+	for _, r := range requests {
+		row, ok := rows[r.StaffID]
+		i, inRange := index[r.Date]
+		if !ok || !inRange || r.Type != domain.RequestTypePaidLeave || row.Cells[i] != 0 {
+			continue
+		}
+		row.Marks[i] = "有"
+		row.PaidLeaveDays++
+		costs[r.StaffID] += paidLeaveHoursPerDay(rc.staff[r.StaffID]) * float64(rc.staff[r.StaffID].HourlyWage)
+	}
+
+	for _, st := range staffList {
+		row := rows[int(st.ID)]
+		row.Cost = int(math.Round(costs[row.StaffID]))
+		grid.TotalHours += row.Hours
+		grid.TotalCost += row.Cost
+		grid.Rows = append(grid.Rows, *row)
+	}
+	return grid, nil
+}
@@ -0,0 +1,267 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"sort"
+)
+
+type VersionRepository interface {
+	Create(version *domain.ScheduleVersion) error
+	FindByPeriod(periodID uint) ([]domain.ScheduleVersion, error)
+	FindByNumber(periodID uint, number int) (*domain.ScheduleVersion, error)
+}
+
+// takeSnapshot: 期間内の今のシフトをスナップショットとして保存する
+func takeSnapshot(shiftRepo ShiftRepository, versionRepo VersionRepository, period *domain.SchedulePeriod, source string, note string) (*domain.ScheduleVersion, error) {
+	shifts, err := shiftRepo.FindRange(period.StartDate, period.EndDate)
+	if err != nil {
+		return nil, err
+	}
+
+	version := &domain.ScheduleVersion{
+		PeriodID: period.ID,
+		Source:   source,
+		Note:     note,
+		Shifts:   []domain.VersionShift{},
+	}
+	for _, s := range shifts {
+		version.Shifts = append(version.Shifts, domain.VersionShift{
+			StaffID:   s.StaffID,
+			Date:      s.Date,
+			ShiftType: s.ShiftType,
+			Locked:    s.Locked,
+		})
+	}
+	if err := versionRepo.Create(version); err != nil {
+		return nil, err
+	}
+	return version, nil
+}
+
+// snapshotIfChanged: 期間の今のシフトが最新のバージョンと違うときだけスナップショットを残す
+// （バージョンがまだ無ければ、シフトが1件でもあれば残す）
+func snapshotIfChanged(shiftRepo ShiftRepository, versionRepo VersionRepository, period *domain.SchedulePeriod, source string, note string) error {
+	shifts, err := shiftRepo.FindRange(period.StartDate, period.EndDate)
+	if err != nil {
+		return err
+	}
+	versions, err := versionRepo.FindByPeriod(period.ID)
+	if err != nil {
+		return err
+	}
+	if len(versions) == 0 {
+		if len(shifts) == 0 {
+			return nil
+		}
+	} else {
+		latest, err := versionRepo.FindByNumber(period.ID, versions[len(versions)-1].Version)
+		if err != nil {
+			return err
+		}
+		if sameAsVersion(shifts, latest.Shifts) {
+			return nil
+		}
+	}
+	_, err = takeSnapshot(shiftRepo, versionRepo, period, source, note)
+	return err
+}
+
+// sameAsVersion: シフトとバージョンの中身が同じか（スタッフ・日付・区分・ロック）
+func sameAsVersion(shifts []domain.Shift, saved []domain.VersionShift) bool {
+	if len(shifts) != len(saved) {
+		return false
+	}
+	type key struct {
+		staffID   int
+		date      string
+		shiftType int
+		locked    bool
+	}
+	counts := make(map[key]int)
+	for _, s := range shifts {
+		counts[key{s.StaffID, s.Date, s.ShiftType, s.Locked}]++
+	}
+	for _, s := range saved {
+		k := key{s.StaffID, s.Date, s.ShiftType, s.Locked}
+		if counts[k] == 0 {
+			return false
+		}
+		counts[k]--
+	}
+	return true
+}
+
+// SaveVersion: 手修正をまとめてスナップショットにする
+func (u *PeriodUsecase) SaveVersion(periodID int, note string) (*domain.ScheduleVersion, error) {
+	period, err := u.periodRepo.FindByID(periodID)
+	if err != nil {
+		return nil, err
+	}
+	return takeSnapshot(u.shiftRepo, u.versionRepo, period, domain.VersionSourceEdit, note)
+}
+
+// ListVersions: 期間のバージョン一覧
+func (u *PeriodUsecase) ListVersions(periodID int) ([]domain.ScheduleVersion, error) {
+	period, err := u.periodRepo.FindByID(periodID)
+	if err != nil {
+		return nil, err
+	}
+	return u.versionRepo.FindByPeriod(period.ID)
+}
+
+// GetVersion: バージョンの中身
+func (u *PeriodUsecase) GetVersion(periodID int, number int) (*domain.ScheduleVersion, error) {
+	return u.versionRepo.FindByNumber(uint(periodID), number)
+}
+
+// DiffVersions: 2つのバージョンをスタッフごとに比較する
+func (u *PeriodUsecase) DiffVersions(periodID int, from int, to int) (*domain.ScheduleDiff, error) {
+	fromVersion, err := u.versionRepo.FindByNumber(uint(periodID), from)
+	if err != nil {
+		return nil, err
+	}
+	toVersion, err := u.versionRepo.FindByNumber(uint(periodID), to)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.ScheduleDiff{
+		FromVersion: from,
+		ToVersion:   to,
+		Staff:       diffVersionShifts(fromVersion.Shifts, toVersion.Shifts),
+	}, nil
+}
+
+// Rollback: 期間のシフトを過去のバージョンの状態に戻す
+// 戻した結果も新しいバージョンとして残す（履歴は消さない）
+func (u *PeriodUsecase) Rollback(periodID int, number int) (*domain.ScheduleVersion, error) {
+	period, err := u.periodRepo.FindByID(periodID)
+	if err != nil {
+		return nil, err
+	}
+	if period.Status == domain.PeriodLocked {
+		return nil, domain.ErrPeriodLocked
+	}
+	target, err := u.versionRepo.FindByNumber(period.ID, number)
+	if err != nil {
+		return nil, err
+	}
+
+	// 削除済みのスタッフのシフトは戻さない
+	staffList, err := u.staffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	exists := make(map[int]bool)
+	for _, s := range staffList {
+		exists[int(s.ID)] = true
+	}
+
+	var shifts []domain.Shift
+	for _, vs := range target.Shifts {
+		if !exists[vs.StaffID] {
+			continue
+		}
+		shifts = append(shifts, domain.Shift{
+			StaffID:   vs.StaffID,
+			Date:      vs.Date,
+			ShiftType: vs.ShiftType,
+			Locked:    vs.Locked,
+		})
+	}
+	if err := u.shiftRepo.ReplaceRange(period.StartDate, period.EndDate, shifts); err != nil {
+		return nil, err
+	}
+	return takeSnapshot(u.shiftRepo, u.versionRepo, period, domain.VersionSourceRollback, fmt.Sprintf("v%dに戻しました", number))
+}
+
+// diffVersionShifts: スタッフごとに 追加 / 削除 / 移動 に分ける
+// 同じ日付でシフト区分が変わったもの、次に同じシフト区分で日付が変わったものを「移動」として組にする
+func diffVersionShifts(from []domain.VersionShift, to []domain.VersionShift) []domain.StaffDiff {
+	removedByStaff := make(map[int][]domain.Assignment)
+	addedByStaff := make(map[int][]domain.Assignment)
+
+	toSet := make(map[int]map[domain.Assignment]bool)
+	for _, s := range to {
+		if toSet[s.StaffID] == nil {
+			toSet[s.StaffID] = make(map[domain.Assignment]bool)
+		}
+		toSet[s.StaffID][domain.Assignment{Date: s.Date, ShiftType: s.ShiftType}] = true
+	}
+	fromSet := make(map[int]map[domain.Assignment]bool)
+	for _, s := range from {
+		a := domain.Assignment{Date: s.Date, ShiftType: s.ShiftType}
+		if fromSet[s.StaffID] == nil {
+			fromSet[s.StaffID] = make(map[domain.Assignment]bool)
+		}
+		fromSet[s.StaffID][a] = true
+		if !toSet[s.StaffID][a] {
+			removedByStaff[s.StaffID] = append(removedByStaff[s.StaffID], a)
+		}
+	}
+	for _, s := range to {
+		a := domain.Assignment{Date: s.Date, ShiftType: s.ShiftType}
+		if !fromSet[s.StaffID][a] {
+			addedByStaff[s.StaffID] = append(addedByStaff[s.StaffID], a)
+		}
+	}
+
+	staffIDs := make(map[int]bool)
+	for id := range removedByStaff {
+		staffIDs[id] = true
+	}
+	for id := range addedByStaff {
+		staffIDs[id] = true
+	}
+
+	diffs := []domain.StaffDiff{}
+	for id := range staffIDs {
+		removed := sortAssignments(removedByStaff[id])
+		added := sortAssignments(addedByStaff[id])
+		diff := domain.StaffDiff{StaffID: id, Added: []domain.Assignment{}, Removed: []domain.Assignment{}, Moved: []domain.MovedAssignment{}}
+
+		// 1. 同じ日付 → シフト区分の変更
+		// 2. 同じシフト区分 → 日付の移動
+		matchers := []func(a, b domain.Assignment) bool{
+			func(a, b domain.Assignment) bool { return a.Date == b.Date },
+			func(a, b domain.Assignment) bool { return a.ShiftType == b.ShiftType },
+		}
+		for _, match := range matchers {
+			var restRemoved []domain.Assignment
+			for _, r := range removed {
+				paired := false
+				for i, a := range added {
+					if match(r, a) {
+						diff.Moved = append(diff.Moved, domain.MovedAssignment{From: r, To: a})
+						added = append(added[:i], added[i+1:]...)
+						paired = true
+						break
+					}
+				}
+				if !paired {
+					restRemoved = append(restRemoved, r)
+				}
+			}
+			removed = restRemoved
+		}
+
+		diff.Removed = append(diff.Removed, removed...)
+		diff.Added = append(diff.Added, added...)
+		diffs = append(diffs, diff)
+	}
+
+	sort.Slice(diffs, func(i, j int) bool { return diffs[i].StaffID < diffs[j].StaffID })
+	return diffs
+}
+
+// sortAssignments: 日付→シフト区分の順に並べる
+func sortAssignments(list []domain.Assignment) []domain.Assignment {
+	sorted := append([]domain.Assignment{}, list...)
+	sort.Slice(sorted, func(i, j int) bool {
+		if sorted[i].Date != sorted[j].Date {
+			return sorted[i].Date < sorted[j].Date
+		}
+		return sorted[i].ShiftType < sorted[j].ShiftType
+	})
+	return sorted
+}
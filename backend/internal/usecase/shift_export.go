@@ -0,0 +1,83 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"time"
+)
+
+// ShiftExportQuery: シフト出力の絞り込み（空の項目では絞り込まない）
+type ShiftExportQuery struct {
+	From     string
+	To       string
+	StaffIDs []int
+	Role     string // この役割を持つスタッフだけ
+}
+
+// ShiftExport: 絞り込み済みのシフト出力。Each で1件ずつ読む
+type ShiftExport struct {
+	Staff  map[int]domain.Staff // スタッフID -> スタッフ（名前・時給などの列に使う）
+	repo   ShiftRepository
+	filter domain.ShiftFilter
+	empty  bool // 条件に合うスタッフがいない
+}
+
+// PrepareShiftExport: 絞り込み条件を確かめ、出力の準備をする
+// 書き出し始めてからはエラーを返せないので、条件の誤りはここで返す
+func (u *ShiftUsecase) PrepareShiftExport(q ShiftExportQuery) (*ShiftExport, error) {
+	for _, d := range []struct{ name, value string }{{"from", q.From}, {"to", q.To}} {
+		if d.value == "" {
+			continue
+		}
+		if _, err := time.Parse(dateLayout, d.value); err != nil {
+			return nil, fmt.Errorf("%w: %s must be YYYY-MM-DD", domain.ErrInvalidInput, d.name)
+		}
+	}
+	if q.From != "" && q.To != "" && q.To < q.From {
+		return nil, fmt.Errorf("%w: from は to 以前の日付を指定してください", domain.ErrInvalidInput)
+	}
+
+	staffList, err := u.staffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	e := &ShiftExport{
+		Staff:  make(map[int]domain.Staff),
+		repo:   u.shiftRepo,
+		filter: domain.ShiftFilter{From: q.From, To: q.To, StaffIDs: q.StaffIDs},
+	}
+	for _, s := range staffList {
+		e.Staff[int(s.ID)] = s
+	}
+	for _, id := range q.StaffIDs {
+		if _, ok := e.Staff[id]; !ok {
+			return nil, fmt.Errorf("%w: staff %d does not exist", domain.ErrInvalidInput, id)
+		}
+	}
+
+	if q.Role != "" {
+		candidates := q.StaffIDs
+		if len(candidates) == 0 {
+			for _, s := range staffList {
+				candidates = append(candidates, int(s.ID))
+			}
+		}
+		var ids []int
+		for _, id := range candidates {
+			if staffHasRole(e.Staff[id], q.Role) {
+				ids = append(ids, id)
+			}
+		}
+		e.filter.StaffIDs = ids
+		e.empty = len(ids) == 0
+	}
+	return e, nil
+}
+
+// Each: 条件に合うシフトを日付順に1件ずつ渡す
+func (e *ShiftExport) Each(fn func(domain.Shift) error) error {
+	if e.empty {
+		return nil
+	}
+	return e.repo.Each(e.filter, fn)
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"smart-shift-scheduler/internal/domain"
 	"smart-shift-scheduler/internal/infrastructure/engine"
+	"sort"
 	"time" // ★追加: 日付計算のために必要
 )
 
@@ -14,22 +15,53 @@ import (
 type ShiftRepository interface {
 	Save(shifts []domain.Shift) error
 	FindAll() ([]domain.Shift, error)
+	FindRange(startDate string, endDate string) ([]domain.Shift, error)
+	FindByID(id int) (*domain.Shift, error)
 	Update(shift *domain.Shift) error
 	Delete(id int) error
 	DeleteByStaffID(staffID int) error
 	DeleteRange(startDate string, endDate string) error // 追加
+	SetLocked(id int, locked bool) error
+	ReplaceRange(startDate string, endDate string, shifts []domain.Shift) error
+	SaveGenerated(startDate string, endDate string, shifts []domain.Shift, openShifts []domain.OpenShift) error
+	Each(filter domain.ShiftFilter, fn func(domain.Shift) error) error
 }
 
 type RequestRepository interface {
 	Save(req *domain.ShiftRequest) error
 	FindAll() ([]domain.ShiftRequest, error)
+	FindByID(id int) (*domain.ShiftRequest, error)
+	FindByStaffRange(staffID int, from string, to string) ([]domain.ShiftRequest, error)
+	Review(req *domain.ShiftRequest) error
 	Delete(id int) error
+	SaveAll(reqs []domain.ShiftRequest) error
 }
 
 type RequirementRepository interface {
 	Save(req *domain.DailyRequirement) error
 	FindAll() ([]domain.DailyRequirement, error)
+	FindByID(id int) (*domain.DailyRequirement, error)
+	FindByDate(date string) (*domain.DailyRequirement, error)
 	Delete(id int) error // 追加
+	SaveAll(reqs []domain.DailyRequirement) error
+}
+
+type PairRuleRepository interface {
+	Save(rule *domain.StaffPairRule) error
+	FindAll() ([]domain.StaffPairRule, error)
+	Delete(id int) error
+}
+
+type RoleConstraintRepository interface {
+	Save(rc *domain.RoleConstraint) error
+	FindAll() ([]domain.RoleConstraint, error)
+	Delete(id int) error
+}
+
+type DayOffRuleRepository interface {
+	Save(rule *domain.DayOffRule) error
+	FindAll() ([]domain.DayOffRule, error)
+	Delete(id int) error
 }
 
 type ShiftUsecase struct {
@@ -38,15 +70,27 @@ type ShiftUsecase struct {
 	shiftRepo   ShiftRepository
 	requestRepo RequestRepository
 	requireRepo RequirementRepository
+	pairRepo    PairRuleRepository
+	dayOffRepo  DayOffRuleRepository
+	periodRepo  PeriodRepository
+	versionRepo VersionRepository
+	roleRepo    RoleConstraintRepository
+	openRepo    OpenShiftRepository
 }
 
-func NewShiftUsecase(engine *engine.ShiftEngine, staffRepo domain.StaffRepository, shiftRepo ShiftRepository, requestRepo RequestRepository, requireRepo RequirementRepository) *ShiftUsecase {
+func NewShiftUsecase(engine *engine.ShiftEngine, staffRepo domain.StaffRepository, shiftRepo ShiftRepository, requestRepo RequestRepository, requireRepo RequirementRepository, pairRepo PairRuleRepository, dayOffRepo DayOffRuleRepository, periodRepo PeriodRepository, versionRepo VersionRepository, roleRepo RoleConstraintRepository, openRepo OpenShiftRepository) *ShiftUsecase {
 	return &ShiftUsecase{
 		engine:      engine,
 		staffRepo:   staffRepo,
 		shiftRepo:   shiftRepo,
 		requestRepo: requestRepo,
 		requireRepo: requireRepo,
+		pairRepo:    pairRepo,
+		dayOffRepo:  dayOffRepo,
+		periodRepo:  periodRepo,
+		versionRepo: versionRepo,
+		roleRepo:    roleRepo,
+		openRepo:    openRepo,
 	}
 }
 
@@ -59,8 +103,8 @@ func (u *ShiftUsecase) GenerateAndSave(input domain.ShiftInput, startDateStr str
 	}
 	input.StaffList = staffList
 
-	// 2. 希望休を取得
-	requests, err := u.requestRepo.FindAll()
+	// 2. 希望休を取得（承認済みのものだけ）
+	requests, err := u.approvedRequests()
 	if err != nil {
 		return err
 	}
@@ -72,15 +116,28 @@ func (u *ShiftUsecase) GenerateAndSave(input domain.ShiftInput, startDateStr str
 		input.Requirements = requirements
 	}
 
-	// 4. Pythonで計算
-	result, err := u.engine.Generate(input)
+	// 役割ごとの人数ルール（リクエストで指定が無ければ保存済みのものを使う）
+	if len(input.RoleConstraints) == 0 {
+		roles, err := u.roleRepo.FindAll()
+		if err != nil {
+			return err
+		}
+		input.RoleConstraints = roles
+	}
+
+	// ペアルールを取得
+	pairRules, err := u.pairRepo.FindAll()
 	if err != nil {
 		return err
 	}
-// Pythonのステータス判定
-	if result.Status != "OPTIMAL" && result.Status != "FEASIBLE" && result.Status != "Optimal" && result.Status != "Feasible" {
-		return fmt.Errorf("解が見つかりませんでした: %s", result.Status)
+	input.PairRules = pairRules
+
+	// 休日ルールを取得し、スタッフごとに解決してから渡す
+	dayOffRules, err := u.dayOffRepo.FindAll()
+	if err != nil {
+		return err
 	}
+	input.DayOffRules = resolveDayOffRules(staffList, dayOffRules)
 
 	layout := "2006-01-02"
 	startDate, err := time.Parse(layout, startDateStr)
@@ -88,15 +145,68 @@ func (u *ShiftUsecase) GenerateAndSave(input domain.ShiftInput, startDateStr str
 		return fmt.Errorf("日付形式エラー: %v", err)
 	}
 
-	// ★追加: 古いシフトを消す処理
-	// 作成期間（デフォルト30日と仮定）の古いデータを削除
-    // input.Days が 0 の場合もあるのでデフォルト30を入れておく
-    days := input.Days
-    if days == 0 { days = 30 }
-    
+	// input.Days が 0 の場合もあるのでデフォルト30を入れておく
+	days := input.Days
+	if days == 0 {
+		days = 30
+	}
+	input.Days = days
+	input.StartDate = startDateStr
+
+	// 確定済みの期間にかかる再生成はできない
 	endDate := startDate.AddDate(0, 0, days-1).Format(layout)
-	if err := u.shiftRepo.DeleteRange(startDateStr, endDate); err != nil {
-		return fmt.Errorf("既存シフト削除失敗: %v", err)
+	if err := u.checkRangeNotLocked(startDateStr, endDate); err != nil {
+		return err
+	}
+
+	// 直前の期間（同じ日数分、少なくとも1週間）の保存済みシフトを実績として渡す
+	// 月またぎの連勤・勤務間インターバル・公平性をPython側で判定するため
+	// （修正モードでは days が残りの日数になるので、短くても連勤を判定できるようにする）
+	historyDays := days
+	if historyDays < 7 {
+		historyDays = 7
+	}
+	historyStart := startDate.AddDate(0, 0, -historyDays).Format(layout)
+	historyEnd := startDate.AddDate(0, 0, -1).Format(layout)
+	history, err := u.shiftRepo.FindRange(historyStart, historyEnd)
+	if err != nil {
+		return err
+	}
+	input.History = history
+
+	// 期間内のロック済みシフトは固定として渡す（消さずにその周りだけ組み直す）
+	current, err := u.shiftRepo.FindRange(startDateStr, endDate)
+	if err != nil {
+		return err
+	}
+	locked := make(map[string]bool) // "staffID/date"
+	input.Fixed = nil
+	for _, s := range current {
+		if s.Locked {
+			input.Fixed = append(input.Fixed, s)
+			locked[fmt.Sprintf("%d/%s", s.StaffID, s.Date)] = true
+		}
+	}
+
+	// 4. Pythonで計算
+	result, err := u.engine.Generate(input)
+	if err != nil {
+		return err
+	}
+// Pythonのステータス判定
+	if result.Status != "OPTIMAL" && result.Status != "FEASIBLE" && result.Status != "Optimal" && result.Status != "Feasible" {
+		return fmt.Errorf("解が見つかりませんでした: %s", result.Status)
+	}
+
+	// 消す前の状態が最新のバージョンと違えば（手修正の後など）、戻せるようにバージョンとして残しておく
+	period, err := u.periodRepo.FindCovering(startDateStr)
+	if err != nil {
+		return err
+	}
+	if period != nil {
+		if err := snapshotIfChanged(u.shiftRepo, u.versionRepo, period, domain.VersionSourceEdit, "生成前の状態"); err != nil {
+			return err
+		}
 	}
 
 	// 5. 結果をDBに保存
@@ -111,6 +221,11 @@ func (u *ShiftUsecase) GenerateAndSave(input domain.ShiftInput, startDateStr str
 			// 開始日 + i日後 を計算して文字列に戻す
 			dateStr := startDate.AddDate(0, 0, i).Format(layout)
 
+			// ロック済みのシフトはDBに残っているので保存しない
+			if locked[fmt.Sprintf("%d/%s", staffID, dateStr)] {
+				continue
+			}
+
 			shifts = append(shifts, domain.Shift{
 				StaffID:   staffID,
 				Date:      dateStr, // "2026-02-02" のようになる
@@ -119,26 +234,461 @@ func (u *ShiftUsecase) GenerateAndSave(input domain.ShiftInput, startDateStr str
 		}
 	}
 
-	return u.shiftRepo.Save(shifts)
+	// 埋まらなかった枠を募集シフトにする
+	var openShifts []domain.OpenShift
+	for _, slot := range result.Unfilled {
+		for i := 0; i < slot.Count; i++ {
+			openShifts = append(openShifts, domain.OpenShift{
+				Date:      slot.Date,
+				ShiftType: slot.ShiftType,
+				Role:      slot.Role,
+				Source:    domain.OpenShiftFromGeneration,
+				Status:    domain.OpenShiftOpen,
+				ClaimMode: domain.ClaimFirstCome,
+			})
+		}
+	}
+	// 作成期間の古いデータ（ロック済みは残る）と前回の生成で出た募集中の枠を、1トランザクションで入れ替える
+	if err := u.shiftRepo.SaveGenerated(startDateStr, endDate, shifts, openShifts); err != nil {
+		return fmt.Errorf("生成結果の保存に失敗しました: %w", err)
+	}
+
+	// 期間に含まれる生成なら、結果をスナップショットとして残す
+	if period != nil {
+		note := fmt.Sprintf("%s から %d 日分を生成", startDateStr, days)
+		if input.Mode == domain.GenerateModeRepair {
+			note = fmt.Sprintf("%s 以降を修正", startDateStr)
+		}
+		if _, err := takeSnapshot(u.shiftRepo, u.versionRepo, period, domain.VersionSourceGenerate, note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OpenShiftsFrom: start から days 日分の募集中の枠（生成結果の確認用）
+func (u *ShiftUsecase) OpenShiftsFrom(start string, days int) ([]domain.OpenShift, error) {
+	if days == 0 {
+		days = 30
+	}
+	return u.openRepo.Find(domain.OpenShiftOpen, start, addDays(start, days-1))
+}
+
+// Generate: 通常の生成。変わった割り当ての一覧を返す（監査ログ・通知用）
+func (u *ShiftUsecase) Generate(input domain.ShiftInput, startDateStr string) ([]domain.ShiftChange, error) {
+	days := input.Days
+	if days == 0 {
+		days = 30
+	}
+	endStr := addDays(startDateStr, days-1)
+	before, err := u.shiftRepo.FindRange(startDateStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	if err := u.GenerateAndSave(input, startDateStr); err != nil {
+		return nil, err
+	}
+	after, err := u.shiftRepo.FindRange(startDateStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	return diffShifts(before, after), nil
+}
+
+// Repair: 修正モード
+// 期間の終わりは変えずに repair_from 以降だけを解き直し、今のシフトからの変更をできるだけ減らす
+// 変わった割り当ての一覧を返す
+func (u *ShiftUsecase) Repair(input domain.ShiftInput, startDateStr string) ([]domain.ShiftChange, error) {
+	layout := "2006-01-02"
+	startDate, err := time.Parse(layout, startDateStr)
+	if err != nil {
+		return nil, fmt.Errorf("日付形式エラー: %v", err)
+	}
+	repairFrom, err := time.Parse(layout, input.RepairFrom)
+	if err != nil {
+		return nil, fmt.Errorf("%w: repair_from の日付形式エラー: %v", domain.ErrInvalidInput, err)
+	}
+
+	days := input.Days
+	if days == 0 {
+		days = 30
+	}
+	endDate := startDate.AddDate(0, 0, days-1)
+	if repairFrom.Before(startDate) || repairFrom.After(endDate) {
+		return nil, fmt.Errorf("%w: repair_from は期間内の日付を指定してください", domain.ErrInvalidInput)
+	}
+
+	// repair_from より前は history として固定され、そこから後ろだけが対象になる
+	fromStr := repairFrom.Format(layout)
+	endStr := endDate.Format(layout)
+	before, err := u.shiftRepo.FindRange(fromStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+
+	input.Mode = domain.GenerateModeRepair
+	input.Days = int(endDate.Sub(repairFrom).Hours()/24) + 1
+	input.Hints = before
+	if err := u.GenerateAndSave(input, fromStr); err != nil {
+		return nil, err
+	}
+
+	after, err := u.shiftRepo.FindRange(fromStr, endStr)
+	if err != nil {
+		return nil, err
+	}
+	return diffShifts(before, after), nil
+}
+
+// diffShifts: スタッフ×日付ごとに、シフト区分が変わったものを並べる
+func diffShifts(before []domain.Shift, after []domain.Shift) []domain.ShiftChange {
+	type cell struct {
+		staffID int
+		date    string
+	}
+	beforeMap := make(map[cell]int)
+	afterMap := make(map[cell]int)
+	for _, s := range before {
+		beforeMap[cell{s.StaffID, s.Date}] = s.ShiftType
+	}
+	for _, s := range after {
+		afterMap[cell{s.StaffID, s.Date}] = s.ShiftType
+	}
+
+	changes := []domain.ShiftChange{}
+	for c, b := range beforeMap {
+		if a := afterMap[c]; a != b {
+			changes = append(changes, domain.ShiftChange{StaffID: c.staffID, Date: c.date, Before: b, After: a})
+		}
+	}
+	for c, a := range afterMap {
+		if _, ok := beforeMap[c]; !ok {
+			changes = append(changes, domain.ShiftChange{StaffID: c.staffID, Date: c.date, Before: 0, After: a})
+		}
+	}
+	sort.Slice(changes, func(i, j int) bool {
+		if changes[i].Date != changes[j].Date {
+			return changes[i].Date < changes[j].Date
+		}
+		return changes[i].StaffID < changes[j].StaffID
+	})
+	return changes
 }
 
 // ... (以下の ListShifts などは変更なし) ...
 func (u *ShiftUsecase) ListShifts() ([]domain.Shift, error) {
 	return u.shiftRepo.FindAll()
 }
-func (u *ShiftUsecase) UpdateShift(shift *domain.Shift) error {
-	return u.shiftRepo.Update(shift)
+func (u *ShiftUsecase) GetShift(id int) (*domain.Shift, error) {
+	return u.shiftRepo.FindByID(id)
+}
+// UpdateShift: シフトを更新する
+// 変更で新しく発生するルール違反を返し、必須ルールの違反があれば override しない限り保存しない
+func (u *ShiftUsecase) UpdateShift(shift *domain.Shift, override bool) ([]domain.Violation, error) {
+	before, err := u.shiftRepo.FindByID(int(shift.ID))
+	if err != nil {
+		return nil, err
+	}
+	// 移動元・移動先のどちらかが確定済みの期間なら変更できない
+	if err := u.checkDateNotLocked(before.Date); err != nil {
+		return nil, err
+	}
+	if shift.Date != "" {
+		if _, err := time.Parse(dateLayout, shift.Date); err != nil {
+			return nil, fmt.Errorf("%w: 日付形式エラー: %v", domain.ErrInvalidInput, err)
+		}
+		if err := u.checkDateNotLocked(shift.Date); err != nil {
+			return nil, err
+		}
+	}
+
+	proposed := *before
+	if shift.Date != "" {
+		proposed.Date = shift.Date
+	}
+	if shift.ShiftType != 0 {
+		proposed.ShiftType = shift.ShiftType
+	}
+
+	violations, err := u.checkChange([]domain.Shift{*before}, []domain.Shift{proposed})
+	if err != nil {
+		return nil, err
+	}
+	if hasHard(violations) && !override {
+		return violations, domain.ErrRuleViolation
+	}
+
+	if err := u.shiftRepo.Update(shift); err != nil {
+		return nil, err
+	}
+	if err := u.snapshotEdit("シフトの変更", before.Date, proposed.Date); err != nil {
+		return nil, err
+	}
+	return violations, nil
+}
+
+// checkChange: シフトを removed から added に置き換えたときに、新しく発生する違反を返す
+// (ID が同じものは置き換え、ID が 0 のものは追加として扱う)
+func (u *ShiftUsecase) checkChange(removed []domain.Shift, added []domain.Shift) ([]domain.Violation, error) {
+	from, to, ok := changeWindow(removed, added)
+	if !ok {
+		return nil, nil
+	}
+	current, err := u.shiftRepo.FindRange(addDays(from, -7), addDays(to, 7))
+	if err != nil {
+		return nil, err
+	}
+	rc, err := u.loadRuleContext()
+	if err != nil {
+		return nil, err
+	}
+	return rc.changeViolations(current, removed, added), nil
+}
+
+// changeViolations: current のうち removed を added に置き換えたときに、新しく発生する違反
+// current には変更する日付の前後2週間分を含めて渡すこと
+func (rc *ruleContext) changeViolations(current []domain.Shift, removed []domain.Shift, added []domain.Shift) []domain.Violation {
+	from, to, ok := changeWindow(removed, added)
+	if !ok {
+		return nil
+	}
+	removedIDs := make(map[uint]bool)
+	for _, s := range removed {
+		removedIDs[s.ID] = true
+	}
+	var proposed []domain.Shift
+	for _, s := range current {
+		if !removedIDs[s.ID] {
+			proposed = append(proposed, s)
+		}
+	}
+	proposed = append(proposed, added...)
+	return newViolations(rc.check(current, from, to), rc.check(proposed, from, to))
+}
+
+// changeWindow: 影響する日付の前後1週間を判定範囲にする（連勤・週休のため）
+func changeWindow(removed []domain.Shift, added []domain.Shift) (string, string, bool) {
+	from, to := "", ""
+	for _, s := range append(append([]domain.Shift{}, removed...), added...) {
+		if from == "" || s.Date < from {
+			from = s.Date
+		}
+		if to == "" || s.Date > to {
+			to = s.Date
+		}
+	}
+	if from == "" {
+		return "", "", false
+	}
+	return addDays(from, -6), addDays(to, 6), true
+}
+
+// LockShift: シフトのロック／ロック解除（確定済みの期間では変えられない）
+func (u *ShiftUsecase) LockShift(id int, locked bool) error {
+	shift, err := u.shiftRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if err := u.checkDateNotLocked(shift.Date); err != nil {
+		return err
+	}
+	return u.shiftRepo.SetLocked(id, locked)
 }
 func (u *ShiftUsecase) DeleteShift(id int) error {
-	return u.shiftRepo.Delete(id)
+	shift, err := u.shiftRepo.FindByID(id)
+	if err != nil {
+		return err
+	}
+	if err := u.checkDateNotLocked(shift.Date); err != nil {
+		return err
+	}
+	if err := u.shiftRepo.Delete(id); err != nil {
+		return err
+	}
+	return u.snapshotEdit("シフトの削除", shift.Date)
+}
+
+// snapshotEdit: 手修正のあと、変わった日付を含む期間ごとにスナップショットを残す（期間外の日付は何もしない）
+func (u *ShiftUsecase) snapshotEdit(note string, dates ...string) error {
+	done := make(map[uint]bool)
+	for _, date := range dates {
+		period, err := u.periodRepo.FindCovering(date)
+		if err != nil {
+			return err
+		}
+		if period == nil || done[period.ID] {
+			continue
+		}
+		done[period.ID] = true
+		if err := snapshotIfChanged(u.shiftRepo, u.versionRepo, period, domain.VersionSourceEdit, note); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ResolvePeriod: period_id が指定されていれば、期間の開始日・日数を入力に反映する
+func (u *ShiftUsecase) ResolvePeriod(input *domain.ShiftInput) error {
+	if input.PeriodID == 0 {
+		return nil
+	}
+	period, err := u.periodRepo.FindByID(int(input.PeriodID))
+	if err != nil {
+		return err
+	}
+	input.StartDate = period.StartDate
+	input.Days = period.Days()
+	return nil
+}
+
+// checkDateNotLocked: その日付を含む期間が確定済みならエラー
+func (u *ShiftUsecase) checkDateNotLocked(date string) error {
+	period, err := u.periodRepo.FindCovering(date)
+	if err != nil {
+		return err
+	}
+	if period != nil && period.Status == domain.PeriodLocked {
+		return domain.ErrPeriodLocked
+	}
+	return nil
 }
+
+// checkRangeNotLocked: 範囲にかかる期間が1つでも確定済みならエラー
+func (u *ShiftUsecase) checkRangeNotLocked(startDate string, endDate string) error {
+	periods, err := u.periodRepo.FindOverlapping(startDate, endDate)
+	if err != nil {
+		return err
+	}
+	for _, p := range periods {
+		if p.Status == domain.PeriodLocked {
+			return domain.ErrPeriodLocked
+		}
+	}
+	return nil
+}
+// CreateRequest: 休み希望の提出（承認待ちで登録する）
+// 期間の締切日を過ぎていたり、月の上限を超える場合は受け付けない
 func (u *ShiftUsecase) CreateRequest(req *domain.ShiftRequest) error {
-	req.Type = "NG"
+	date, err := time.Parse(dateLayout, req.Date)
+	if err != nil {
+		return fmt.Errorf("%w: 日付形式エラー: %v", domain.ErrInvalidInput, err)
+	}
+	if req.Type == "" {
+		req.Type = domain.RequestTypeNG
+	}
+	if req.Type != domain.RequestTypeNG && req.Type != domain.RequestTypePaidLeave {
+		return fmt.Errorf("%w: type は NG か PAID を指定してください", domain.ErrInvalidInput)
+	}
+
+	staffList, err := u.staffRepo.FindAll()
+	if err != nil {
+		return err
+	}
+	var staff *domain.Staff
+	for i := range staffList {
+		if int(staffList[i].ID) == req.StaffID {
+			staff = &staffList[i]
+		}
+	}
+	if staff == nil {
+		return fmt.Errorf("%w: スタッフが見つかりません", domain.ErrInvalidInput)
+	}
+
+	// 締切（公開済み・確定済みの期間は受付終了）
+	period, err := u.periodRepo.FindCovering(req.Date)
+	if err != nil {
+		return err
+	}
+	if period != nil {
+		if period.Status != domain.PeriodDraft {
+			return fmt.Errorf("%w: 期間は公開済みです", domain.ErrRequestClosed)
+		}
+		if deadline := period.RequestDeadline(); deadline != "" && time.Now().Format(dateLayout) > deadline {
+			return fmt.Errorf("%w: 締切日は%sでした", domain.ErrRequestClosed, deadline)
+		}
+	}
+
+	// 同じ月の休み希望（却下されたものは数えない）
+	monthStart := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, time.UTC)
+	monthEnd := monthStart.AddDate(0, 1, -1)
+	existing, err := u.requestRepo.FindByStaffRange(req.StaffID, monthStart.Format(dateLayout), monthEnd.Format(dateLayout))
+	if err != nil {
+		return err
+	}
+	count := 0
+	for _, r := range existing {
+		if r.Status == domain.RequestRejected {
+			continue
+		}
+		if r.Date == req.Date {
+			return fmt.Errorf("%w: %sの休み希望は提出済みです", domain.ErrInvalidInput, req.Date)
+		}
+		// 有給は法律上の権利なので、休み希望の上限には数えない
+		if r.Type != domain.RequestTypePaidLeave {
+			count++
+		}
+	}
+
+	if req.Type == domain.RequestTypePaidLeave {
+		if err := u.checkPaidLeaveBalance(*staff, req.Date); err != nil {
+			return err
+		}
+	} else if staff.MaxRequestDaysPerMonth > 0 && count >= staff.MaxRequestDaysPerMonth {
+		return fmt.Errorf("%w: %d月は%d日までです", domain.ErrRequestLimit, int(date.Month()), staff.MaxRequestDaysPerMonth)
+	}
+
+	req.ID = 0
+	req.Status = domain.RequestPending
+	req.Comment = ""
+	req.ReviewedBy = ""
+	req.ReviewedAt = nil
 	return u.requestRepo.Save(req)
 }
+
+// ReviewRequest: 休み希望の承認・却下（承認待ちのものだけ）
+func (u *ShiftUsecase) ReviewRequest(id int, approve bool, reviewer string, comment string) (*domain.ShiftRequest, error) {
+	req, err := u.requestRepo.FindByID(id)
+	if err != nil {
+		return nil, domain.ErrNotFound
+	}
+	if req.Status != domain.RequestPending {
+		return nil, domain.ErrInvalidRequestStatus
+	}
+
+	now := time.Now()
+	req.Status = domain.RequestRejected
+	if approve {
+		req.Status = domain.RequestApproved
+	}
+	req.Comment = comment
+	req.ReviewedBy = reviewer
+	req.ReviewedAt = &now
+	if err := u.requestRepo.Review(req); err != nil {
+		return nil, err
+	}
+	return req, nil
+}
+
+// approvedRequests: 承認済みの休み希望（ソルバーとルール判定に使う）
+func (u *ShiftUsecase) approvedRequests() ([]domain.ShiftRequest, error) {
+	requests, err := u.requestRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	var approved []domain.ShiftRequest
+	for _, r := range requests {
+		if r.Status == domain.RequestApproved {
+			approved = append(approved, r)
+		}
+	}
+	return approved, nil
+}
 func (u *ShiftUsecase) ListRequests() ([]domain.ShiftRequest, error) {
 	return u.requestRepo.FindAll()
 }
+func (u *ShiftUsecase) GetRequest(id int) (*domain.ShiftRequest, error) {
+	return u.requestRepo.FindByID(id)
+}
 func (u *ShiftUsecase) DeleteRequest(id int) error {
 	return u.requestRepo.Delete(id)
 }
@@ -148,10 +698,74 @@ func (u *ShiftUsecase) SaveRequirement(req *domain.DailyRequirement) error {
 func (u *ShiftUsecase) GetRequirements() ([]domain.DailyRequirement, error) {
 	return u.requireRepo.FindAll()
 }
+func (u *ShiftUsecase) GetRequirement(id int) (*domain.DailyRequirement, error) {
+	return u.requireRepo.FindByID(id)
+}
+func (u *ShiftUsecase) FindRequirementByDate(date string) (*domain.DailyRequirement, error) {
+	return u.requireRepo.FindByDate(date)
+}
 // ⭕️ Usecaseにはこれを貼る
 func (u *ShiftUsecase) DeleteRequirement(id int) error {
 	return u.requireRepo.Delete(id)
 }
+func (u *ShiftUsecase) SavePairRule(rule *domain.StaffPairRule) error {
+	return u.pairRepo.Save(rule)
+}
+func (u *ShiftUsecase) ListPairRules() ([]domain.StaffPairRule, error) {
+	return u.pairRepo.FindAll()
+}
+func (u *ShiftUsecase) DeletePairRule(id int) error {
+	return u.pairRepo.Delete(id)
+}
+
+func (u *ShiftUsecase) SaveRoleConstraint(rc *domain.RoleConstraint) error {
+	return u.roleRepo.Save(rc)
+}
+func (u *ShiftUsecase) ListRoleConstraints() ([]domain.RoleConstraint, error) {
+	return u.roleRepo.FindAll()
+}
+func (u *ShiftUsecase) DeleteRoleConstraint(id int) error {
+	return u.roleRepo.Delete(id)
+}
+
+func (u *ShiftUsecase) SaveDayOffRule(rule *domain.DayOffRule) error {
+	return u.dayOffRepo.Save(rule)
+}
+func (u *ShiftUsecase) ListDayOffRules() ([]domain.DayOffRule, error) {
+	return u.dayOffRepo.FindAll()
+}
+func (u *ShiftUsecase) DeleteDayOffRule(id int) error {
+	return u.dayOffRepo.Delete(id)
+}
+
+// resolveDayOffRules: スタッフごとに適用する休日ルールを決める
+// スタッフ個別のルール > 雇用区分のルール の順で優先し、どちらも無ければ対象外
+func resolveDayOffRules(staffList []domain.Staff, rules []domain.DayOffRule) []domain.DayOffRule {
+	byStaff := make(map[int]domain.DayOffRule)
+	byType := make(map[string]domain.DayOffRule)
+	for _, r := range rules {
+		if r.StaffID != 0 {
+			byStaff[r.StaffID] = r
+		} else if r.EmploymentType != "" {
+			byType[r.EmploymentType] = r
+		}
+	}
+
+	var resolved []domain.DayOffRule
+	for _, s := range staffList {
+		rule, ok := byStaff[int(s.ID)]
+		if !ok {
+			rule, ok = byType[s.EmploymentType]
+		}
+		if !ok {
+			continue
+		}
+		rule.StaffID = int(s.ID)
+		resolved = append(resolved, rule)
+	}
+	return resolved
+}
+
 // ListStaff: スタッフ一覧を取得 (Handler用)
 func (u *ShiftUsecase) ListStaff() ([]domain.Staff, error) {
 	return u.staffRepo.FindAll()
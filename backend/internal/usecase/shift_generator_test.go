@@ -0,0 +1,60 @@
+package usecase
+
+import (
+	"reflect"
+	"smart-shift-scheduler/internal/domain"
+	"testing"
+)
+
+func TestDiffShifts(t *testing.T) {
+	tests := []struct {
+		name   string
+		before []domain.Shift
+		after  []domain.Shift
+		want   []domain.ShiftChange
+	}{
+		{
+			name:   "変更なし",
+			before: []domain.Shift{{ID: 1, StaffID: 1, Date: "2026-02-02", ShiftType: 1}},
+			after:  []domain.Shift{{ID: 9, StaffID: 1, Date: "2026-02-02", ShiftType: 1}},
+			want:   []domain.ShiftChange{},
+		},
+		{
+			name:   "区分の変更",
+			before: []domain.Shift{{StaffID: 1, Date: "2026-02-02", ShiftType: 1}},
+			after:  []domain.Shift{{StaffID: 1, Date: "2026-02-02", ShiftType: 2}},
+			want:   []domain.ShiftChange{{StaffID: 1, Date: "2026-02-02", Before: 1, After: 2}},
+		},
+		{
+			name:   "追加と削除は休み（0）との差にする",
+			before: []domain.Shift{{StaffID: 1, Date: "2026-02-02", ShiftType: 1}},
+			after:  []domain.Shift{{StaffID: 2, Date: "2026-02-02", ShiftType: 1}},
+			want: []domain.ShiftChange{
+				{StaffID: 1, Date: "2026-02-02", Before: 1, After: 0},
+				{StaffID: 2, Date: "2026-02-02", Before: 0, After: 1},
+			},
+		},
+		{
+			name: "日付順、同じ日はスタッフ順",
+			before: []domain.Shift{
+				{StaffID: 3, Date: "2026-02-03", ShiftType: 2},
+				{StaffID: 2, Date: "2026-02-02", ShiftType: 1},
+			},
+			after: []domain.Shift{
+				{StaffID: 1, Date: "2026-02-03", ShiftType: 1},
+			},
+			want: []domain.ShiftChange{
+				{StaffID: 2, Date: "2026-02-02", Before: 1, After: 0},
+				{StaffID: 1, Date: "2026-02-03", Before: 0, After: 1},
+				{StaffID: 3, Date: "2026-02-03", Before: 2, After: 0},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := diffShifts(tt.before, tt.after); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("diffShifts = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,234 @@
+package usecase
+
+import (
+	"errors"
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"strings"
+)
+
+// スタッフ名簿の取り込み方
+const (
+	StaffImportCreate = "create" // 新しい人だけ登録する（社員番号が登録済みならエラー）
+	StaffImportUpsert = "upsert" // 社員番号が登録済みなら更新、無ければ登録
+)
+
+// staffColumn: 名簿の列。見出しは英語のキーか日本語の別名で書ける
+type staffColumn struct {
+	key     string
+	aliases []string
+	apply   func(s *domain.Staff, v string) error
+}
+
+var staffColumns = []staffColumn{
+	{key: "employee_code", aliases: []string{"社員番号", "従業員番号", "code"}, apply: func(s *domain.Staff, v string) error {
+		s.EmployeeCode = v
+		return nil
+	}},
+	{key: "name", aliases: []string{"名前", "氏名", "スタッフ名"}, apply: func(s *domain.Staff, v string) error {
+		s.Name = v
+		return nil
+	}},
+	{key: "hourly_wage", aliases: []string{"時給", "wage"}, apply: func(s *domain.Staff, v string) error {
+		n, err := parseImportInt(v)
+		s.HourlyWage = n
+		return err
+	}},
+	{key: "roles", aliases: []string{"役割"}, apply: func(s *domain.Staff, v string) error {
+		s.Roles = normalizeRoles(v)
+		return nil
+	}},
+	{key: "is_leader", aliases: []string{"リーダー", "leader"}, apply: func(s *domain.Staff, v string) error {
+		b, err := parseImportBool(v)
+		s.IsLeader = b
+		return err
+	}},
+	{key: "employment_type", aliases: []string{"雇用区分"}, apply: func(s *domain.Staff, v string) error {
+		switch strings.ToLower(v) {
+		case domain.EmploymentFullTime, "正社員", "フルタイム":
+			s.EmploymentType = domain.EmploymentFullTime
+		case domain.EmploymentPartTime, "パート", "アルバイト", "パート・アルバイト":
+			s.EmploymentType = domain.EmploymentPartTime
+		default:
+			return fmt.Errorf("%s か %s を指定してください", domain.EmploymentFullTime, domain.EmploymentPartTime)
+		}
+		return nil
+	}},
+	{key: "max_request_days_per_month", aliases: []string{"休み希望上限"}, apply: func(s *domain.Staff, v string) error {
+		n, err := parseImportInt(v)
+		s.MaxRequestDaysPerMonth = n
+		return err
+	}},
+	{key: "hire_date", aliases: []string{"入社日"}, apply: func(s *domain.Staff, v string) error {
+		date, err := parseImportDate(v)
+		s.HireDate = date
+		return err
+	}},
+	{key: "weekly_scheduled_days", aliases: []string{"週所定日数"}, apply: func(s *domain.Staff, v string) error {
+		n, err := parseImportInt(v)
+		if err == nil && n > 7 {
+			err = errors.New("0〜7 で指定してください")
+		}
+		s.WeeklyScheduledDays = n
+		return err
+	}},
+	{key: "weekly_scheduled_hours", aliases: []string{"週所定時間"}, apply: func(s *domain.Staff, v string) error {
+		n, err := parseImportNumber(v)
+		if err == nil && (n < 0 || n > 7*24) {
+			err = errors.New("0〜168 で指定してください")
+		}
+		s.WeeklyScheduledHours = n
+		return err
+	}},
+	{key: "max_weekly_hours", aliases: []string{"週上限時間"}, apply: func(s *domain.Staff, v string) error {
+		n, err := parseImportNumber(v)
+		if err == nil && (n < 0 || n > 7*24) {
+			err = errors.New("0〜168 で指定してください")
+		}
+		s.MaxWeeklyHours = n
+		return err
+	}},
+	{key: "annual_income_cap", aliases: []string{"年収上限"}, apply: func(s *domain.Staff, v string) error {
+		n, err := parseImportInt(v)
+		s.AnnualIncomeCap = n
+		return err
+	}},
+}
+
+// ImportStaff: スタッフ名簿（CSV・Excel）を取り込む
+// 空のセルは「変更しない」（新規登録なら未設定のまま）。エラーが1件でもあれば何も保存しない
+// dryRun なら確認だけして保存しない
+func (u *StaffUsecase) ImportStaff(table *domain.ImportTable, mode string, dryRun bool) (*domain.StaffImportResult, error) {
+	if mode == "" {
+		mode = StaffImportCreate
+	}
+	if mode != StaffImportCreate && mode != StaffImportUpsert {
+		return nil, fmt.Errorf("%w: mode は %s か %s を指定してください", domain.ErrInvalidInput, StaffImportCreate, StaffImportUpsert)
+	}
+
+	type present struct {
+		index int
+		staffColumn
+	}
+	var columns []present // ファイルにある列（staffColumns の順）
+	codeCol, nameCol := -1, -1
+	for _, col := range staffColumns {
+		i := table.Column(append([]string{col.key}, col.aliases...)...)
+		if i < 0 {
+			continue
+		}
+		columns = append(columns, present{index: i, staffColumn: col})
+		switch col.key {
+		case "employee_code":
+			codeCol = i
+		case "name":
+			nameCol = i
+		}
+	}
+	if nameCol < 0 && mode == StaffImportCreate {
+		return nil, fmt.Errorf("%w: name 列が必要です", domain.ErrInvalidInput)
+	}
+	if codeCol < 0 && mode == StaffImportUpsert {
+		return nil, fmt.Errorf("%w: upsert では employee_code 列が必要です", domain.ErrInvalidInput)
+	}
+
+	existing, err := u.repo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	byCode := make(map[string]domain.Staff)
+	for _, s := range existing {
+		if s.EmployeeCode != "" {
+			byCode[s.EmployeeCode] = s
+		}
+	}
+
+	result := &domain.StaffImportResult{DryRun: dryRun}
+	var errs importErrors
+	seen := make(map[string]int) // 社員番号 -> 最初に出てきた行
+	for _, row := range table.Rows {
+		code := row.Value(codeCol)
+		entry := domain.StaffImportRow{Line: row.Line, Action: domain.ImportActionCreate}
+		if before, ok := byCode[code]; ok && code != "" {
+			if mode == StaffImportCreate {
+				errs.add(row.Line, table.Header[codeCol], fmt.Sprintf("社員番号 %s はすでに登録されています（更新するなら mode=upsert）", code))
+				continue
+			}
+			entry.Action = domain.ImportActionUpdate
+			entry.Before = &before
+			entry.Staff = before
+		} else if mode == StaffImportUpsert && code == "" {
+			errs.add(row.Line, table.Header[codeCol], "upsert では社員番号が必要です")
+			continue
+		}
+		if code != "" {
+			if first, dup := seen[code]; dup {
+				errs.add(row.Line, table.Header[codeCol], fmt.Sprintf("社員番号 %s が重複しています（%d 行目）", code, first))
+				continue
+			}
+			seen[code] = row.Line
+		}
+
+		valid := true
+		for _, col := range columns {
+			v := row.Value(col.index)
+			if v == "" {
+				continue
+			}
+			if err := col.apply(&entry.Staff, v); err != nil {
+				errs.add(row.Line, table.Header[col.index], err.Error())
+				valid = false
+			}
+		}
+		if entry.Staff.Name == "" {
+			errs.add(row.Line, "name", "名前を入力してください")
+			valid = false
+		}
+		if !valid {
+			continue
+		}
+
+		if entry.Action == domain.ImportActionCreate {
+			result.Created++
+		} else {
+			result.Updated++
+		}
+		result.Rows = append(result.Rows, entry)
+	}
+	result.Errors = errs
+
+	if len(result.Errors) > 0 {
+		if dryRun {
+			return result, nil
+		}
+		return result, fmt.Errorf("%w: %d 行にエラーがあるため、取り込みませんでした", domain.ErrInvalidInput, len(result.Errors))
+	}
+	if dryRun || len(result.Rows) == 0 {
+		return result, nil
+	}
+
+	staff := make([]domain.Staff, len(result.Rows))
+	for i, entry := range result.Rows {
+		staff[i] = entry.Staff
+	}
+	if err := u.repo.SaveAll(staff); err != nil {
+		return nil, err
+	}
+	for i := range result.Rows {
+		result.Rows[i].Staff = staff[i] // 登録した人の ID を返す
+	}
+	result.Applied = true
+	return result, nil
+}
+
+// normalizeRoles: "Kitchen、Hall" / "Kitchen/Hall" -> "Kitchen,Hall"
+func normalizeRoles(v string) string {
+	v = strings.NewReplacer("、", ",", "，", ",", "/", ",", "・", ",").Replace(v)
+	var roles []string
+	for _, r := range strings.Split(v, ",") {
+		if r = strings.TrimSpace(r); r != "" {
+			roles = append(roles, r)
+		}
+	}
+	return strings.Join(roles, ",")
+}
@@ -1,12 +1,17 @@
 package usecase
 
-import "smart-shift-scheduler/internal/domain"
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+)
 
 // StaffRepository: データ保存のインターフェース
 type StaffRepository interface {
 	Save(staff *domain.Staff) error
 	FindAll() ([]domain.Staff, error)
+	FindByID(id uint) (*domain.Staff, error)
 	Delete(id uint) error // ★追加
+	SaveAll(staff []domain.Staff) error
 }
 
 type StaffUsecase struct {
@@ -18,6 +23,18 @@ func NewStaffUsecase(repo StaffRepository) *StaffUsecase {
 }
 
 func (u *StaffUsecase) CreateStaff(staff *domain.Staff) error {
+	// 社員番号は一括取り込みで同じ人を探すのに使うので重複させない
+	if staff.EmployeeCode != "" {
+		list, err := u.repo.FindAll()
+		if err != nil {
+			return err
+		}
+		for _, s := range list {
+			if s.EmployeeCode == staff.EmployeeCode {
+				return fmt.Errorf("%w: employee_code %s is already registered", domain.ErrInvalidInput, staff.EmployeeCode)
+			}
+		}
+	}
 	return u.repo.Save(staff)
 }
 
@@ -25,6 +42,11 @@ func (u *StaffUsecase) GetAllStaff() ([]domain.Staff, error) {
 	return u.repo.FindAll()
 }
 
+// GetStaff: 1件取得
+func (u *StaffUsecase) GetStaff(id uint) (*domain.Staff, error) {
+	return u.repo.FindByID(id)
+}
+
 // DeleteStaff: スタッフ削除（★追加！）
 func (u *StaffUsecase) DeleteStaff(id uint) error {
 	return u.repo.Delete(id)
@@ -0,0 +1,224 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+)
+
+type SwapRepository interface {
+	Create(swap *domain.ShiftSwap, event *domain.SwapEvent) error
+	Find(status string, staffID int) ([]domain.ShiftSwap, error)
+	FindByID(id int) (*domain.ShiftSwap, error)
+	FindOpenByShift(shiftID uint) ([]domain.ShiftSwap, error)
+	UpdateStatus(swap *domain.ShiftSwap, event *domain.SwapEvent) error
+	Apply(swap *domain.ShiftSwap, before []domain.Shift, after []domain.Shift, event *domain.SwapEvent) error
+}
+
+// SwapUsecase: スタッフ間のシフト交換（申請 → 相手の承諾 → 店長の承認）
+type SwapUsecase struct {
+	swapRepo SwapRepository
+	shifts   *ShiftUsecase // ルール判定・確定済み期間のチェックに使う
+}
+
+func NewSwapUsecase(swapRepo SwapRepository, shifts *ShiftUsecase) *SwapUsecase {
+	return &SwapUsecase{swapRepo: swapRepo, shifts: shifts}
+}
+
+// SwapApproval: 承認して反映した結果（監査ログ用に付け替え前後のシフトも返す）
+type SwapApproval struct {
+	Swap       *domain.ShiftSwap
+	Before     []domain.Shift
+	After      []domain.Shift
+	Violations []domain.Violation // 警告（必須ルールの違反があれば反映しない）
+}
+
+// ProposeSwap: 交換を申請する
+// この時点でもルール判定をして、必須ルールに違反する交換は受け付けない
+func (u *SwapUsecase) ProposeSwap(swap *domain.ShiftSwap, actor string) ([]domain.Violation, error) {
+	if swap.Type != domain.SwapGive && swap.Type != domain.SwapTrade {
+		return nil, fmt.Errorf("%w: type は give か trade を指定してください", domain.ErrInvalidInput)
+	}
+	if swap.RequesterID == swap.TargetID {
+		return nil, fmt.Errorf("%w: 自分自身とは交換できません", domain.ErrInvalidInput)
+	}
+	if swap.Type == domain.SwapGive {
+		swap.TargetShiftID = 0
+	}
+
+	staffList, err := u.shifts.staffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	found := false
+	for _, s := range staffList {
+		if int(s.ID) == swap.TargetID {
+			found = true
+		}
+	}
+	if !found {
+		return nil, fmt.Errorf("%w: 相手のスタッフが見つかりません", domain.ErrInvalidInput)
+	}
+
+	before, after, err := u.swapShifts(swap)
+	if err != nil {
+		return nil, err
+	}
+	for _, s := range before {
+		open, err := u.swapRepo.FindOpenByShift(s.ID)
+		if err != nil {
+			return nil, err
+		}
+		if len(open) > 0 {
+			return nil, fmt.Errorf("%w: シフト(ID:%d)には処理中の交換申請があります", domain.ErrInvalidSwapStatus, s.ID)
+		}
+	}
+
+	violations, err := u.checkSwap(before, after)
+	if err != nil {
+		return violations, err
+	}
+
+	swap.ID = 0
+	swap.Status = domain.SwapProposed
+	swap.History = nil
+	event := &domain.SwapEvent{ToStatus: domain.SwapProposed, Actor: actor, Comment: swap.Note}
+	if err := u.swapRepo.Create(swap, event); err != nil {
+		return nil, err
+	}
+	swap.History = []domain.SwapEvent{*event}
+	return violations, nil
+}
+
+func (u *SwapUsecase) ListSwaps(status string, staffID int) ([]domain.ShiftSwap, error) {
+	return u.swapRepo.Find(status, staffID)
+}
+
+func (u *SwapUsecase) GetSwap(id int) (*domain.ShiftSwap, error) {
+	return u.swapRepo.FindByID(id)
+}
+
+// AcceptSwap: 相手が承諾する（店長の承認待ちになる）
+func (u *SwapUsecase) AcceptSwap(id int, actor string, comment string) (*domain.ShiftSwap, error) {
+	return u.transition(id, []string{domain.SwapProposed}, domain.SwapAccepted, actor, comment)
+}
+
+// DeclineSwap: 相手が断る
+func (u *SwapUsecase) DeclineSwap(id int, actor string, comment string) (*domain.ShiftSwap, error) {
+	return u.transition(id, []string{domain.SwapProposed}, domain.SwapDeclined, actor, comment)
+}
+
+// CancelSwap: 申請者が取り下げる（承認前ならいつでも可）
+func (u *SwapUsecase) CancelSwap(id int, actor string, comment string) (*domain.ShiftSwap, error) {
+	return u.transition(id, []string{domain.SwapProposed, domain.SwapAccepted}, domain.SwapCancelled, actor, comment)
+}
+
+// RejectSwap: 店長が却下する
+func (u *SwapUsecase) RejectSwap(id int, actor string, comment string) (*domain.ShiftSwap, error) {
+	return u.transition(id, []string{domain.SwapProposed, domain.SwapAccepted}, domain.SwapRejected, actor, comment)
+}
+
+// ApproveSwap: 店長が承認し、シフトを付け替える
+// 申請後に他のシフトが変わっている可能性があるので、ここでもう一度ルール判定する
+func (u *SwapUsecase) ApproveSwap(id int, actor string, comment string) (*SwapApproval, error) {
+	swap, err := u.swapRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if swap.Status != domain.SwapAccepted {
+		return nil, domain.ErrInvalidSwapStatus
+	}
+
+	before, after, err := u.swapShifts(swap)
+	if err != nil {
+		return nil, err
+	}
+	violations, err := u.checkSwap(before, after)
+	if err != nil {
+		return &SwapApproval{Swap: swap, Violations: violations}, err
+	}
+
+	event := &domain.SwapEvent{FromStatus: swap.Status, ToStatus: domain.SwapApproved, Actor: actor, Comment: comment}
+	if err := u.swapRepo.Apply(swap, before, after, event); err != nil {
+		return nil, err
+	}
+	swap.History = append(swap.History, *event)
+	var dates []string
+	for _, s := range after {
+		dates = append(dates, s.Date)
+	}
+	if err := u.shifts.snapshotEdit("シフト交換の承認", dates...); err != nil {
+		return nil, err
+	}
+	return &SwapApproval{Swap: swap, Before: before, After: after, Violations: violations}, nil
+}
+
+// transition: 状態だけを変える操作（シフトは変えない）
+func (u *SwapUsecase) transition(id int, from []string, to string, actor string, comment string) (*domain.ShiftSwap, error) {
+	swap, err := u.swapRepo.FindByID(id)
+	if err != nil {
+		return nil, err
+	}
+	allowed := false
+	for _, status := range from {
+		if swap.Status == status {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return nil, domain.ErrInvalidSwapStatus
+	}
+
+	event := &domain.SwapEvent{FromStatus: swap.Status, ToStatus: to, Actor: actor, Comment: comment}
+	if err := u.swapRepo.UpdateStatus(swap, event); err != nil {
+		return nil, err
+	}
+	swap.History = append(swap.History, *event)
+	return swap, nil
+}
+
+// swapShifts: 交換前のシフトと、交換後（スタッフを付け替えた）シフト
+func (u *SwapUsecase) swapShifts(swap *domain.ShiftSwap) ([]domain.Shift, []domain.Shift, error) {
+	mine, err := u.shifts.shiftRepo.FindByID(int(swap.ShiftID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: シフト(ID:%d)が見つかりません", domain.ErrInvalidInput, swap.ShiftID)
+	}
+	if mine.StaffID != swap.RequesterID {
+		return nil, nil, fmt.Errorf("%w: シフト(ID:%d)は申請者のシフトではありません", domain.ErrInvalidInput, mine.ID)
+	}
+	given := *mine
+	given.StaffID = swap.TargetID
+	before := []domain.Shift{*mine}
+	after := []domain.Shift{given}
+
+	if swap.Type == domain.SwapTrade {
+		theirs, err := u.shifts.shiftRepo.FindByID(int(swap.TargetShiftID))
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: シフト(ID:%d)が見つかりません", domain.ErrInvalidInput, swap.TargetShiftID)
+		}
+		if theirs.StaffID != swap.TargetID {
+			return nil, nil, fmt.Errorf("%w: シフト(ID:%d)は相手のシフトではありません", domain.ErrInvalidInput, theirs.ID)
+		}
+		taken := *theirs
+		taken.StaffID = swap.RequesterID
+		before = append(before, *theirs)
+		after = append(after, taken)
+	}
+	return before, after, nil
+}
+
+// checkSwap: 確定済みの期間でないことと、ルール違反を確認する
+func (u *SwapUsecase) checkSwap(before []domain.Shift, after []domain.Shift) ([]domain.Violation, error) {
+	for _, s := range before {
+		if err := u.shifts.checkDateNotLocked(s.Date); err != nil {
+			return nil, err
+		}
+	}
+	violations, err := u.shifts.checkChange(before, after)
+	if err != nil {
+		return nil, err
+	}
+	if hasHard(violations) {
+		return violations, domain.ErrRuleViolation
+	}
+	return violations, nil
+}
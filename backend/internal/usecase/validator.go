@@ -0,0 +1,384 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Python側 (engine/main.py) と同じ値にしておくこと
+const (
+	maxConsecutiveDays = 5  // 最大連勤日数
+	minRestHours       = 11 // 勤務間インターバル（時間）
+	defaultMorningNeed = 2  // 日別設定が無いときの早番の必要人数
+	defaultEveningNeed = 2  // 日別設定が無いときの遅番の必要人数
+	dateLayout         = "2006-01-02"
+)
+
+// ruleContext: ルール判定に必要なデータ一式
+type ruleContext struct {
+	staff        map[int]domain.Staff
+	requests     map[string]bool // "staffID/date" -> 休み希望あり
+	requirements map[string]domain.DailyRequirement
+	roles        []domain.RoleConstraint
+	pairRules    []domain.StaffPairRule
+	dayOffRules  map[int]domain.DayOffRule // スタッフごとに解決済み
+}
+
+// loadRuleContext: DBから判定用のデータを集める
+func (u *ShiftUsecase) loadRuleContext() (*ruleContext, error) {
+	staffList, err := u.staffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	requests, err := u.approvedRequests()
+	if err != nil {
+		return nil, err
+	}
+	requirements, err := u.requireRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	roles, err := u.roleRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	pairRules, err := u.pairRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	dayOffRules, err := u.dayOffRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	rc := &ruleContext{
+		staff:        make(map[int]domain.Staff),
+		requests:     make(map[string]bool),
+		requirements: make(map[string]domain.DailyRequirement),
+		roles:        roles,
+		pairRules:    pairRules,
+		dayOffRules:  make(map[int]domain.DayOffRule),
+	}
+	for _, s := range staffList {
+		rc.staff[int(s.ID)] = s
+	}
+	for _, r := range requests {
+		rc.requests[cellKey(r.StaffID, r.Date)] = true
+	}
+	for _, r := range requirements {
+		rc.requirements[r.Date] = r
+	}
+	for _, r := range resolveDayOffRules(staffList, dayOffRules) {
+		rc.dayOffRules[r.StaffID] = r
+	}
+	return rc, nil
+}
+
+// check: shifts のうち、from〜to の日付にかかる違反を返す
+// 連勤・週休の判定のため、shifts には範囲の前後1週間分も含めて渡すこと
+func (rc *ruleContext) check(shifts []domain.Shift, from string, to string) []domain.Violation {
+	var violations []domain.Violation
+	inRange := func(date string) bool { return date >= from && date <= to }
+
+	// スタッフ×日付ごとの出勤
+	byStaff := make(map[int]map[string][]domain.Shift)
+	for _, s := range shifts {
+		if s.ShiftType == 0 {
+			continue
+		}
+		if byStaff[s.StaffID] == nil {
+			byStaff[s.StaffID] = make(map[string][]domain.Shift)
+		}
+		byStaff[s.StaffID][s.Date] = append(byStaff[s.StaffID][s.Date], s)
+	}
+
+	names := rc.staffNames()
+	for staffID, days := range byStaff {
+		name := staffLabel(names, staffID)
+
+		for date, list := range days {
+			if !inRange(date) {
+				continue
+			}
+			// 1. 同じ日に2つ以上のシフト
+			if len(list) > 1 {
+				violations = append(violations, domain.Violation{Rule: "double_booking", Level: domain.ViolationHard, StaffID: staffID, Date: date,
+					Message: fmt.Sprintf("%sは%sに%d件のシフトが入っています", name, date, len(list))})
+			}
+			// 2. 希望休の日に出勤
+			if rc.requests[cellKey(staffID, date)] {
+				violations = append(violations, domain.Violation{Rule: "ng_request", Level: domain.ViolationHard, StaffID: staffID, Date: date,
+					Message: fmt.Sprintf("%sは%sに休み希望を出しています", name, date)})
+			}
+			// 3. 連勤（その日で何連勤目か。上限を超えた日はすべて違反にする）
+			streak := 1
+			for d := addDays(date, -1); len(days[d]) > 0; d = addDays(d, -1) {
+				streak++
+			}
+			if streak > maxConsecutiveDays {
+				violations = append(violations, domain.Violation{Rule: "consecutive_days", Level: domain.ViolationHard, StaffID: staffID, Date: date,
+					Message: fmt.Sprintf("%sは%sで%d連勤になります（最大%d連勤）", name, date, streak, maxConsecutiveDays)})
+			}
+			// 4. 勤務間インターバル（前日の終業から当日の始業まで）
+			// 自動生成では rest_interval を指定したときだけ守るルールなので、ここでも警告に留める
+			for _, prev := range days[addDays(date, -1)] {
+				for _, cur := range list {
+					if rest, ok := restHours(prev.ShiftType, cur.ShiftType); ok && rest < minRestHours {
+						violations = append(violations, domain.Violation{Rule: "rest_interval", Level: domain.ViolationSoft, StaffID: staffID, Date: date,
+							Message: fmt.Sprintf("%sの%sは前日からの休息が%.0f時間しかありません（%d時間以上必要）", name, date, rest, minRestHours)})
+					}
+				}
+			}
+			// 5. 飛び石の休み（前日が休みで、前々日は出勤）
+			rule, hasRule := rc.dayOffRules[staffID]
+			off := addDays(date, -1)
+			if hasRule && rule.PreferConsecutive && inRange(off) && len(days[off]) == 0 && len(days[addDays(date, -2)]) > 0 {
+				violations = append(violations, domain.Violation{Rule: "single_day_off", Level: domain.ViolationSoft, StaffID: staffID, Date: off,
+					Message: fmt.Sprintf("%sの%sの休みが連休になっていません", name, off)})
+			}
+		}
+
+		// 6. 週休N日（暦週＝日曜〜土曜）
+		rule, ok := rc.dayOffRules[staffID]
+		if ok && rule.MinWeeklyDaysOff > 0 {
+			for _, weekStart := range weekStarts(from, to) {
+				worked := 0
+				for i := 0; i < 7; i++ {
+					if len(days[addDays(weekStart, i)]) > 0 {
+						worked++
+					}
+				}
+				if 7-worked < rule.MinWeeklyDaysOff {
+					violations = append(violations, domain.Violation{Rule: "weekly_days_off", Level: domain.ViolationHard, StaffID: staffID, Date: weekStart,
+						Message: fmt.Sprintf("%sの%sからの週の休みが%d日です（週%d日以上必要）", name, weekStart, 7-worked, rule.MinWeeklyDaysOff)})
+				}
+			}
+		}
+	}
+
+	// 7. ペアルール
+	for _, v := range validatePairRules(shifts, rc.pairRules, names) {
+		if inRange(v.Date) {
+			violations = append(violations, v)
+		}
+	}
+
+	// 8. 必要人数・役割ごとの人数（日付単位）
+	for date := from; date <= to; date = addDays(date, 1) {
+		for _, t := range domain.ShiftTemplates {
+			need := rc.need(date, t.Type)
+			got := 0
+			for _, s := range shifts {
+				if s.Date == date && s.ShiftType == t.Type {
+					got++
+				}
+			}
+			if got < need {
+				violations = append(violations, domain.Violation{Rule: "understaffed", Level: domain.ViolationHard, Date: date,
+					Message: fmt.Sprintf("%sの%sが%d人です（%d人必要）", date, t.Name, got, need)})
+			}
+		}
+		for _, role := range rc.roles {
+			got := 0
+			for _, s := range shifts {
+				if s.Date == date && s.ShiftType != 0 && rc.hasRole(s.StaffID, role.Role) {
+					got++
+				}
+			}
+			if got < role.Count {
+				violations = append(violations, domain.Violation{Rule: "role_minimum", Level: domain.ViolationHard, Date: date,
+					Message: fmt.Sprintf("%sの%sが%d人です（%d人必要）", date, role.Role, got, role.Count)})
+			}
+		}
+	}
+
+	sortViolations(violations)
+	return violations
+}
+
+// need: その日・そのシフト区分の必要人数
+func (rc *ruleContext) need(date string, shiftType int) int {
+	req, ok := rc.requirements[date]
+	switch shiftType {
+	case 1:
+		if ok {
+			return req.MorningNeed
+		}
+		return defaultMorningNeed
+	case 2:
+		if ok {
+			return req.EveningNeed
+		}
+		return defaultEveningNeed
+	}
+	return 0
+}
+
+// hasRole: Python側と同じく、rolesに含まれているか（Leaderは is_leader でも可）
+func (rc *ruleContext) hasRole(staffID int, role string) bool {
+	s, ok := rc.staff[staffID]
+	if !ok {
+		return false
+	}
+	return staffHasRole(s, role)
+}
+
+// staffHasRole: 役割を持っているか（リーダーは IsLeader でも可）
+func staffHasRole(s domain.Staff, role string) bool {
+	return strings.Contains(s.Roles, role) || (role == "Leader" && s.IsLeader)
+}
+
+func (rc *ruleContext) staffNames() map[int]string {
+	names := make(map[int]string)
+	for id, s := range rc.staff {
+		names[id] = s.Name
+	}
+	return names
+}
+
+// newViolations: after にあって before に無い違反（今回の変更で増えたもの）
+func newViolations(before []domain.Violation, after []domain.Violation) []domain.Violation {
+	seen := make(map[string]bool)
+	for _, v := range before {
+		seen[violationKey(v)] = true
+	}
+	result := []domain.Violation{}
+	for _, v := range after {
+		if !seen[violationKey(v)] {
+			result = append(result, v)
+		}
+	}
+	return result
+}
+
+func violationKey(v domain.Violation) string {
+	return fmt.Sprintf("%s/%d/%s/%s", v.Rule, v.StaffID, v.Date, v.Message)
+}
+
+// hasHard: 必須ルールの違反が含まれているか
+func hasHard(violations []domain.Violation) bool {
+	for _, v := range violations {
+		if v.Level == domain.ViolationHard {
+			return true
+		}
+	}
+	return false
+}
+
+// restHours: 前日のシフト終了から当日のシフト開始までの時間
+func restHours(prevType int, curType int) (float64, bool) {
+	prev, ok1 := domain.FindShiftTemplate(prevType)
+	cur, ok2 := domain.FindShiftTemplate(curType)
+	if !ok1 || !ok2 {
+		return 0, false
+	}
+	end, _ := time.Parse("15:04", prev.End)
+	start, _ := time.Parse("15:04", cur.Start)
+	return start.Add(24 * time.Hour).Sub(end).Hours(), true
+}
+
+// weekStarts: from〜to にかかる週の日曜日の一覧
+func weekStarts(from string, to string) []string {
+	start, err := time.Parse(dateLayout, from)
+	if err != nil {
+		return nil
+	}
+	start = start.AddDate(0, 0, -int(start.Weekday()))
+	var list []string
+	for d := start.Format(dateLayout); d <= to; d = addDays(d, 7) {
+		list = append(list, d)
+	}
+	return list
+}
+
+// addDays: "2026-02-01" の n 日後（不正な日付はそのまま返す）
+func addDays(date string, n int) string {
+	t, err := time.Parse(dateLayout, date)
+	if err != nil {
+		return date
+	}
+	return t.AddDate(0, 0, n).Format(dateLayout)
+}
+
+func cellKey(staffID int, date string) string {
+	return fmt.Sprintf("%d/%s", staffID, date)
+}
+
+// validatePairRules: ペアルール（一緒に入る／一緒に入らない）の違反を探す
+func validatePairRules(shifts []domain.Shift, rules []domain.StaffPairRule, staffNames map[int]string) []domain.Violation {
+	// 日付×シフト区分ごとに、入っているスタッフをまとめる
+	slots := make(map[slotKey]map[int]bool)
+	for _, s := range shifts {
+		if s.ShiftType == 0 {
+			continue
+		}
+		key := slotKey{date: s.Date, shiftType: s.ShiftType}
+		if slots[key] == nil {
+			slots[key] = make(map[int]bool)
+		}
+		slots[key][s.StaffID] = true
+	}
+
+	var violations []domain.Violation
+	for _, rule := range rules {
+		for key, members := range slots {
+			if !members[rule.StaffID] {
+				continue
+			}
+			switch rule.Type {
+			case domain.PairTogether:
+				if !members[rule.PartnerID] {
+					violations = append(violations, domain.Violation{
+						Rule:    "pair_together",
+						Level:   domain.ViolationHard,
+						StaffID: rule.StaffID,
+						Date:    key.date,
+						Message: fmt.Sprintf("%sは%sと同じシフトに入る必要があります", staffLabel(staffNames, rule.StaffID), staffLabel(staffNames, rule.PartnerID)),
+					})
+				}
+			case domain.PairApart:
+				if members[rule.PartnerID] {
+					violations = append(violations, domain.Violation{
+						Rule:    "pair_apart",
+						Level:   domain.ViolationHard,
+						StaffID: rule.StaffID,
+						Date:    key.date,
+						Message: fmt.Sprintf("%sと%sは同じシフトに入れません", staffLabel(staffNames, rule.StaffID), staffLabel(staffNames, rule.PartnerID)),
+					})
+				}
+			}
+		}
+	}
+	sortViolations(violations)
+	return violations
+}
+
+// slotKey: 日付×シフト区分
+type slotKey struct {
+	date      string
+	shiftType int
+}
+
+// sortViolations: 日付→スタッフ→ルール順に並べる（mapの走査順で結果が揺れないように）
+func sortViolations(violations []domain.Violation) {
+	sort.SliceStable(violations, func(i, j int) bool {
+		if violations[i].Date != violations[j].Date {
+			return violations[i].Date < violations[j].Date
+		}
+		if violations[i].StaffID != violations[j].StaffID {
+			return violations[i].StaffID < violations[j].StaffID
+		}
+		return violations[i].Rule < violations[j].Rule
+	})
+}
+
+// staffLabel: メッセージ用のスタッフ名（見つからなければID）
+func staffLabel(staffNames map[int]string, staffID int) string {
+	if name, ok := staffNames[staffID]; ok {
+		return name
+	}
+	return fmt.Sprintf("ID:%d", staffID)
+}
@@ -0,0 +1,190 @@
+package usecase
+
+import (
+	"fmt"
+	"smart-shift-scheduler/internal/domain"
+	"testing"
+)
+
+// 2026-02-01 は日曜日
+func TestRuleContextCheck(t *testing.T) {
+	work := func(staffID int, shiftType int, dates ...string) []domain.Shift {
+		var shifts []domain.Shift
+		for _, date := range dates {
+			shifts = append(shifts, domain.Shift{StaffID: staffID, Date: date, ShiftType: shiftType})
+		}
+		return shifts
+	}
+	join := func(lists ...[]domain.Shift) []domain.Shift {
+		var shifts []domain.Shift
+		for _, list := range lists {
+			shifts = append(shifts, list...)
+		}
+		return shifts
+	}
+
+	tests := []struct {
+		name   string
+		rc     func(rc *ruleContext)
+		shifts []domain.Shift
+		from   string
+		to     string
+		rule   string
+		want   []string // "staffID/date"
+	}{
+		{
+			name:   "同じ日に2つのシフト",
+			shifts: join(work(1, 1, "2026-02-02"), work(1, 2, "2026-02-02")),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "double_booking",
+			want:   []string{"1/2026-02-02"},
+		},
+		{
+			name:   "休み希望の日に出勤",
+			rc:     func(rc *ruleContext) { rc.requests[cellKey(1, "2026-02-03")] = true },
+			shifts: work(1, 1, "2026-02-03", "2026-02-04"),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "ng_request",
+			want:   []string{"1/2026-02-03"},
+		},
+		{
+			name:   "5連勤までは違反にならない",
+			shifts: work(1, 1, "2026-02-01", "2026-02-02", "2026-02-03", "2026-02-04", "2026-02-05"),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "consecutive_days",
+		},
+		{
+			name:   "上限を超えた日はすべて違反",
+			shifts: work(1, 1, "2026-02-01", "2026-02-02", "2026-02-03", "2026-02-04", "2026-02-05", "2026-02-06", "2026-02-07"),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "consecutive_days",
+			want:   []string{"1/2026-02-06", "1/2026-02-07"},
+		},
+		{
+			name:   "範囲より前からの連勤も数える",
+			shifts: work(1, 1, "2026-01-27", "2026-01-28", "2026-01-29", "2026-01-30", "2026-01-31", "2026-02-01", "2026-02-02"),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "consecutive_days",
+			want:   []string{"1/2026-02-01", "1/2026-02-02"},
+		},
+		{
+			name:   "遅番の翌日の早番",
+			shifts: join(work(1, 2, "2026-02-02"), work(1, 1, "2026-02-03"), work(2, 1, "2026-02-02"), work(2, 2, "2026-02-03")),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "rest_interval",
+			want:   []string{"1/2026-02-03"},
+		},
+		{
+			name:   "週休が足りない",
+			rc:     func(rc *ruleContext) { rc.dayOffRules[1] = domain.DayOffRule{StaffID: 1, MinWeeklyDaysOff: 2} },
+			shifts: work(1, 1, "2026-02-01", "2026-02-02", "2026-02-04", "2026-02-05", "2026-02-06", "2026-02-07"),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "weekly_days_off",
+			want:   []string{"1/2026-02-01"},
+		},
+		{
+			name:   "飛び石の休み",
+			rc:     func(rc *ruleContext) { rc.dayOffRules[1] = domain.DayOffRule{StaffID: 1, PreferConsecutive: true} },
+			shifts: work(1, 1, "2026-02-02", "2026-02-04"),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "single_day_off",
+			want:   []string{"1/2026-02-03"},
+		},
+		{
+			name: "一緒に入るべき相手がいない",
+			rc: func(rc *ruleContext) {
+				rc.pairRules = []domain.StaffPairRule{{StaffID: 1, PartnerID: 2, Type: domain.PairTogether}}
+			},
+			shifts: join(work(1, 1, "2026-02-02", "2026-02-03"), work(2, 1, "2026-02-03")),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "pair_together",
+			want:   []string{"1/2026-02-02"},
+		},
+		{
+			name: "同じシフトに入れない2人",
+			rc: func(rc *ruleContext) {
+				rc.pairRules = []domain.StaffPairRule{{StaffID: 1, PartnerID: 2, Type: domain.PairApart}}
+			},
+			shifts: join(work(1, 1, "2026-02-02", "2026-02-03"), work(2, 1, "2026-02-02"), work(2, 2, "2026-02-03")),
+			from:   "2026-02-01",
+			to:     "2026-02-07",
+			rule:   "pair_apart",
+			want:   []string{"1/2026-02-02"},
+		},
+		{
+			name:   "日別設定が無ければ早番・遅番とも2人必要",
+			shifts: work(1, 1, "2026-02-02"),
+			from:   "2026-02-02",
+			to:     "2026-02-02",
+			rule:   "understaffed",
+			want:   []string{"0/2026-02-02", "0/2026-02-02"},
+		},
+		{
+			name: "日別設定の人数を満たしていれば不足なし",
+			rc: func(rc *ruleContext) {
+				rc.requirements["2026-02-02"] = domain.DailyRequirement{Date: "2026-02-02", MorningNeed: 1, EveningNeed: 0}
+			},
+			shifts: work(1, 1, "2026-02-02"),
+			from:   "2026-02-02",
+			to:     "2026-02-02",
+			rule:   "understaffed",
+		},
+		{
+			name: "役割ごとの人数",
+			rc: func(rc *ruleContext) {
+				rc.roles = []domain.RoleConstraint{{Role: "Kitchen", Count: 1}}
+			},
+			shifts: join(work(1, 1, "2026-02-02"), work(2, 1, "2026-02-03")),
+			from:   "2026-02-02",
+			to:     "2026-02-03",
+			rule:   "role_minimum",
+			want:   []string{"0/2026-02-02"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rc := &ruleContext{
+				staff: map[int]domain.Staff{
+					1: {ID: 1, Name: "佐藤"},
+					2: {ID: 2, Name: "鈴木", Roles: "Kitchen"},
+				},
+				requests:     make(map[string]bool),
+				requirements: make(map[string]domain.DailyRequirement),
+				dayOffRules:  make(map[int]domain.DayOffRule),
+			}
+			if tt.rc != nil {
+				tt.rc(rc)
+			}
+			var got []string
+			for _, v := range rc.check(tt.shifts, tt.from, tt.to) {
+				if v.Rule == tt.rule {
+					got = append(got, fmt.Sprintf("%d/%s", v.StaffID, v.Date))
+				}
+			}
+			if !equalStrings(got, tt.want) {
+				t.Errorf("%s = %v, want %v", tt.rule, got, tt.want)
+			}
+		})
+	}
+}
+
+func equalStrings(a []string, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
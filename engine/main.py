@@ -30,6 +30,9 @@ def main():
     
     model = cp_model.CpModel()
 
+    # ソフト制約のペナルティ (最後にまとめて最小化する)
+    penalties = []
+
     # シフト変数の作成
     # shifts[(staff_id, day, shift_type)]
     # shift_type: 0=休み, 1=早番, 2=遅番
@@ -46,18 +49,39 @@ def main():
 
     # --- 制約条件 ---
 
-    # 1. 希望休 (NG) の反映
-    # Go側で日付計算した結果、requestsには "date" 文字列が入っているが、
-    # ここでは簡易的に「何日目か」を特定するのが難しい（Python側でカレンダーを持っていないため）。
-    # そのため、Goから送るときに "day_index" を送るか、
-    # 簡易的に start_date とのマッチングを行う必要がある。
-    # ★今回は以前のロジックを踏襲し、Go側が startDate ベースで処理している前提で、
-    # requests のデータ形式次第だが、今回は「希望休は一旦無視」して連勤防止を優先実装するか、
-    # あるいは以前のコードのように "request['date']" を日付計算してマッピングする。
-    
-    # ※今回は安全のため「連勤防止」と「必要人数」に集中します。
-    # 希望休ロジックは既存のままで動くように、シンプルな日付インデックスマッチングは省略します。
-    # (本格対応するにはGo側で day_index を計算して渡すのがベスト)
+    # Python標準ライブラリだけで日付計算する (start_date からの日数に変換するのに使う)
+    from datetime import datetime, timedelta
+
+    base_date = None
+    if start_date_str:
+        try:
+            base_date = datetime.strptime(start_date_str, '%Y-%m-%d')
+        except:
+            pass
+
+    # 1. 希望休の反映
+    # Go側で承認済みの希望（NG・有給）だけを送ってくるので、その日は休み (shift_type=0) に固定する
+    # 形式: [{'staff_id': 1, 'date': '2026-02-03', 'type': 'NG', 'status': 'approved'}, ...]
+    if base_date:
+        for r in requests:
+            try:
+                d = (datetime.strptime(r['date'], '%Y-%m-%d') - base_date).days
+            except (KeyError, ValueError):
+                continue
+            key = (r.get('staff_id'), d, 0)
+            if 0 <= d < days and key in shifts:
+                model.Add(shifts[key] == 1)
+
+    # 募集シフトを許可する場合は、人数不足を INFEASIBLE にせず「埋まらなかった枠」として返す
+    # 不足1人ごとに大きなペナルティを付けて、埋められる枠はできるだけ埋める
+    allow_open_shifts = data.get('allow_open_shifts', False)
+    open_shift_weight = 1000
+    shortages = [] # (date_str, shift_type, role, IntVar)
+
+    def shortage_var(name, need):
+        short = model.NewIntVar(0, max(need, 0), name)
+        penalties.append(open_shift_weight * short)
+        return short
 
     # 2. 1日あたりの必要人数（全体）
     # デフォルト: 早番2人、遅番2人
@@ -69,19 +93,6 @@ def main():
     for r in requirements:
         req_map[r['date']] = r
 
-    # 日付計算用ライブラリがないので、start_date文字操作は避けるが、
-    # ここでは「requirementsの適用」を簡易化：
-    # もし requirements の中に start_date + d 日後の日付があれば適用するロジックが必要。
-    # Python標準ライブラリだけで日付計算する
-    from datetime import datetime, timedelta
-    
-    base_date = None
-    if start_date_str:
-        try:
-            base_date = datetime.strptime(start_date_str, '%Y-%m-%d')
-        except:
-            pass
-
     for d in range(days):
         # その日の目標人数を決める
         morning_need = default_morning
@@ -96,6 +107,14 @@ def main():
                 morning_need = req_map[curr_str]['morning_need']
                 evening_need = req_map[curr_str]['evening_need']
 
+        if allow_open_shifts:
+            date_str = (base_date + timedelta(days=d)).strftime('%Y-%m-%d') if base_date else str(d)
+            for t, need in [(1, morning_need), (2, evening_need)]:
+                short = shortage_var(f'short_d{d}_t{t}', need)
+                model.Add(sum(shifts[(s['id'], d, t)] for s in staff_list) + short >= need)
+                shortages.append((date_str, t, '', short))
+            continue
+
         # 早番 (shift_type=1) の人数
         model.Add(sum(shifts[(s['id'], d, 1)] for s in staff_list) >= morning_need)
         # 遅番 (shift_type=2) の人数
@@ -113,19 +132,173 @@ def main():
             qualified_staff = [s for s in staff_list if target_role in s.get('roles', '') or (target_role == 'Leader' and s.get('is_leader'))]
             
             # 働いている (shift_type=1 or 2) スタッフの合計
-            model.Add(sum(shifts[(s['id'], d, t)] for s in qualified_staff for t in [1, 2]) >= min_count)
+            worked_count = sum(shifts[(s['id'], d, t)] for s in qualified_staff for t in [1, 2])
+            if allow_open_shifts:
+                # 役割の不足は1日単位なので、シフト区分は決めない (shift_type=0) で返す
+                date_str = (base_date + timedelta(days=d)).strftime('%Y-%m-%d') if base_date else str(d)
+                short = shortage_var(f'short_d{d}_{target_role}', min_count)
+                model.Add(worked_count + short >= min_count)
+                shortages.append((date_str, 0, target_role, short))
+                continue
+            model.Add(worked_count >= min_count)
+
+    # --- 前期間の実績 (history) ---
+    # start_date より前の保存済みシフト。日付インデックスはマイナス (-1 = 前日) で持つ
+    # 形式: [{'staff_id': 1, 'date': '2026-01-31', 'shift_type': 2}, ...]
+    history = {}
+    if base_date:
+        for h in data.get('history') or []:
+            try:
+                offset = (datetime.strptime(h['date'], '%Y-%m-%d') - base_date).days
+            except (KeyError, ValueError):
+                continue
+            if offset < 0 and h.get('shift_type', 0) in [1, 2]:
+                history[(h['staff_id'], offset)] = h['shift_type']
+
+    def worked(staff_id, day):
+        # 期間内なら「出勤(1か2)」の式、期間前なら実績の 0/1 を返す
+        if day >= 0:
+            return sum(shifts[(staff_id, day, t)] for t in [1, 2])
+        return 1 if (staff_id, day) in history else 0
+
+    # --- ロック済みシフト (fixed) ---
+    # 手修正してロックしたシフトは、その割り当てのまま固定する
+    # 形式: [{'staff_id': 1, 'date': '2026-02-03', 'shift_type': 1}, ...]
+    if base_date:
+        for f in data.get('fixed') or []:
+            try:
+                offset = (datetime.strptime(f['date'], '%Y-%m-%d') - base_date).days
+            except (KeyError, ValueError):
+                continue
+            key = (f['staff_id'], offset, f.get('shift_type', 0))
+            if 0 <= offset < days and key in shifts:
+                model.Add(shifts[key] == 1)
 
     # --- ★ここが追加！ブラックバイト防止機能 ---
     
     # 4. 連勤制限 (最大5連勤まで = 6日連続出勤は禁止)
+    # 前期間の末尾も含めて判定する (1月末に5連勤なら2/1は休み)
     max_consecutive_days = 5
     for s in staff_list:
         # window size = max + 1
         window = max_consecutive_days + 1
-        for d in range(days - window + 1):
+        for d in range(-(window - 1), days - window + 1):
             # 期間 [d, d+window-1] の中で、働いている日(1か2)の合計は max 以下でなければならない
             # つまり、6日間の窓の中で「出勤」は最大5回まで（＝最低1回は休み）
-            model.Add(sum(shifts[(s['id'], day, t)] for day in range(d, d + window) for t in [1, 2]) <= max_consecutive_days)
+            model.Add(sum(worked(s['id'], day) for day in range(d, d + window)) <= max_consecutive_days)
+
+    # 5. ペアルール (シフト区分ごとに判定)
+    # together: staff_id が入るなら partner_id も同じシフトに入る
+    # apart: 2人を同じシフトに入れない
+    pair_rules = data.get('pair_rules', [])
+    staff_ids = set(s['id'] for s in staff_list)
+    for rule in pair_rules:
+        a = rule['staff_id']
+        b = rule['partner_id']
+        # 削除済みスタッフなどが混ざっていたら無視する
+        if a not in staff_ids or b not in staff_ids:
+            continue
+        for d in range(days):
+            for t in [1, 2]:
+                if rule['type'] == 'together':
+                    model.Add(shifts[(a, d, t)] <= shifts[(b, d, t)])
+                elif rule['type'] == 'apart':
+                    model.Add(shifts[(a, d, t)] + shifts[(b, d, t)] <= 1)
+
+    # 6. 休日ルール (Go側でスタッフごとに解決済み)
+    # 形式: [{'staff_id': 1, 'min_weekly_days_off': 2, 'prefer_consecutive': true}, ...]
+    day_off_rules = {r['staff_id']: r for r in data.get('day_off_rules', [])}
+
+    # 暦週 (日曜〜土曜) ごとに日付インデックスをまとめる
+    # start_date が無い場合は先頭から7日ずつ区切る
+    weeks = {}
+    for d in range(days):
+        if base_date:
+            current_date = base_date + timedelta(days=d)
+            week_start = current_date - timedelta(days=(current_date.weekday() + 1) % 7)
+        else:
+            week_start = d // 7
+        weeks.setdefault(week_start, []).append(d)
+
+    for s in staff_list:
+        rule = day_off_rules.get(s['id'])
+        if not rule:
+            continue
+
+        # 6-1. 週休N日
+        # 期間前の日は実績で判定し、期間後の日は休みとみなして、期間内で足りない分だけ要求する
+        min_off = rule.get('min_weekly_days_off', 0)
+        for week_days in weeks.values():
+            outside = 7 - len(week_days)
+            if week_days[0] == 0 and base_date:
+                before = (base_date.weekday() + 1) % 7
+                outside -= sum(worked(s['id'], d) for d in range(-before, 0))
+            need = min_off - outside
+            if need > 0:
+                model.Add(sum(shifts[(s['id'], d, 0)] for d in week_days) >= need)
+
+        # 6-2. 連休の優先 (ソフト制約)
+        # 前後が出勤の「飛び石の休み」1日ごとにペナルティ
+        if rule.get('prefer_consecutive'):
+            for d in range(1, days - 1):
+                single_off = model.NewBoolVar(f'single_off_s{s["id"]}_d{d}')
+                model.Add(single_off >= shifts[(s['id'], d, 0)] - shifts[(s['id'], d - 1, 0)] - shifts[(s['id'], d + 1, 0)])
+                penalties.append(single_off)
+
+    # 7. 勤務間インターバル (rest_interval を指定したときだけ。遅番の翌日に早番は入れない)
+    # 前期間の最終日が遅番なら、初日の早番も禁止
+    if data.get('rest_interval'):
+        for s in staff_list:
+            for d in range(days - 1):
+                model.Add(shifts[(s['id'], d, 2)] + shifts[(s['id'], d + 1, 1)] <= 1)
+            if days > 0 and history.get((s['id'], -1)) == 2:
+                model.Add(shifts[(s['id'], 0, 1)] == 0)
+
+    # 8. 公平性 (fairness を指定したときだけ。ソフト制約)
+    # 前期間の出勤日数を足した合計で、スタッフ間の差 (最大 - 最小) を小さくする
+    if data.get('fairness') and staff_list:
+        history_count = {}
+        for (staff_id, _), _ in history.items():
+            history_count[staff_id] = history_count.get(staff_id, 0) + 1
+
+        totals = []
+        for s in staff_list:
+            carried = history_count.get(s['id'], 0)
+            total = model.NewIntVar(0, carried + days, f'total_s{s["id"]}')
+            model.Add(total == carried + sum(worked(s['id'], d) for d in range(days)))
+            totals.append(total)
+        max_total = model.NewIntVar(0, 2 * days + 1, 'max_total')
+        min_total = model.NewIntVar(0, 2 * days + 1, 'min_total')
+        model.AddMaxEquality(max_total, totals)
+        model.AddMinEquality(min_total, totals)
+        penalties.append(max_total - min_total)
+
+    # 9. 修正モード (repair): 既存シフトからの変更を最小にする (ソフト制約)
+    # hints は今保存されているシフト。hint と違う割り当て1マスごとに stability_weight のペナルティ
+    if data.get('mode') == 'repair':
+        stability_weight = data.get('stability_weight') or 10
+        hints = {}
+        if base_date:
+            for h in data.get('hints') or []:
+                try:
+                    offset = (datetime.strptime(h['date'], '%Y-%m-%d') - base_date).days
+                except (KeyError, ValueError):
+                    continue
+                if 0 <= offset < days:
+                    hints[(h['staff_id'], offset)] = h.get('shift_type', 0)
+
+        for s in staff_list:
+            for d in range(days):
+                current = hints.get((s['id'], d), 0)
+                if (s['id'], d, current) not in shifts:
+                    current = 0
+                # 今のシフトを初期解としても渡しておく
+                for t in shift_types:
+                    model.AddHint(shifts[(s['id'], d, t)], 1 if t == current else 0)
+                penalties.append(stability_weight * (1 - shifts[(s['id'], d, current)]))
+
+    if penalties:
+        model.Minimize(sum(penalties))
 
     # --- ソルバー実行 ---
     solver = cp_model.CpSolver()
@@ -147,6 +320,14 @@ def main():
                     staff_schedule.append(0)
             schedule[s['id']] = staff_schedule
         result['schedule'] = schedule
+
+        # 埋まらなかった枠
+        unfilled = []
+        for date_str, t, role, short in shortages:
+            count = solver.Value(short)
+            if count > 0:
+                unfilled.append({'date': date_str, 'shift_type': t, 'role': role, 'count': count})
+        result['unfilled'] = unfilled
     else:
         result['status'] = 'INFEASIBLE'
 